@@ -0,0 +1,349 @@
+// Package client is a Go client of the movies API. Responses are decoded into
+// the same dto and models types the server encodes them from, and error
+// responses are returned as *Error with the problem the API answered with.
+//
+//	movies, err := client.New("http://localhost:8080", client.WithToken(token))
+//	if err != nil {
+//		return err
+//	}
+//
+//	for movie, err := range movies.AllMovies(ctx, client.ListOptions{Limit: 50}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(movie.Title)
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+)
+
+// version of the client, sent in the User-Agent header. It follows the API
+// version it was written against, see @version in main.go
+const Version = "1.0.0"
+
+// requests go to the routes of this API version
+const apiVersion = "v1"
+
+type Client struct {
+	baseUrl    *url.URL
+	httpClient *http.Client
+	token      string
+	language   string
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+type Option func(*Client)
+
+// send requests with this client instead of http.DefaultClient. Its timeout
+// also applies to WatchMovies streams, prefer deadlines on the context.
+func WithHttpClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// authenticate as the subject of this bearer JWT, see the Authentication
+// section of the README
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// send as Accept-Language, for localized movies and problem messages
+func WithLanguage(language string) Option {
+	return func(c *Client) {
+		c.language = language
+	}
+}
+
+// retry failed requests up to retries times, waiting backoff before the
+// first retry and doubling it on each one up to maxBackoff. Zero retries
+// turns retrying off.
+func WithRetries(retries int, backoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// a client of the API served at baseUrl, e.g. http://localhost:8080
+func New(baseUrl string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("base url %q must be http or https", baseUrl)
+	}
+
+	c := &Client{
+		baseUrl:    parsed,
+		httpClient: http.DefaultClient,
+		retries:    3,
+		backoff:    200 * time.Millisecond,
+		maxBackoff: 5 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// a problem the API answered with, switch on its Code rather than its
+// message. Responses that aren't problems, e.g. from a proxy in between,
+// only have a Status and Title.
+type Error struct {
+	dto.Problem
+}
+
+func (err *Error) Error() string {
+	message := fmt.Sprintf("movies api: %d %s", err.Status, err.Title)
+
+	if err.Code != "" {
+		message += " (" + string(err.Code) + ")"
+	}
+	if err.Detail != "" {
+		message += ": " + err.Detail
+	}
+
+	return message
+}
+
+// whether err is a problem with the given code
+func IsCode(err error, code dto.ErrorCode) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// page and limit default to the first page and the server's default limit,
+// lang to the client's language
+type ListOptions struct {
+	Page  int
+	Limit int
+	Lang  string
+}
+
+func (opts ListOptions) values() url.Values {
+	query := url.Values{}
+	setInt(query, "page", opts.Page)
+	setInt(query, "limit", opts.Limit)
+	setString(query, "lang", opts.Lang)
+
+	return query
+}
+
+func setString(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func setInt(query url.Values, key string, value int) {
+	if value != 0 {
+		query.Set(key, strconv.Itoa(value))
+	}
+}
+
+// decode the response to the request into T, any path segments are escaped
+func call[T any](ctx context.Context, c *Client, method string, query url.Values, body any, path ...string) (T, error) {
+	var out T
+
+	resp, err := c.send(ctx, method, query, body, nil, path...)
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return out, fmt.Errorf("decode %s %s: %w", method, resp.Request.URL.Path, err)
+	}
+
+	return out, nil
+}
+
+// the data of a dto.DataResponse
+func data[T any](ctx context.Context, c *Client, method string, query url.Values, body any, path ...string) (T, error) {
+	response, err := call[dto.DataResponse[T]](ctx, c, method, query, body, path...)
+	return response.Data, err
+}
+
+// every item from the given page on, fetching the next page once the items
+// of the current one are used up. Items created or deleted in the meantime
+// shift the pages, so an item can be skipped or seen twice.
+func all[T any](ctx context.Context, c *Client, opts ListOptions, query url.Values, path ...string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for key, values := range opts.values() {
+			query[key] = values
+		}
+
+		page := max(opts.Page, 1)
+
+		for {
+			setInt(query, "page", page)
+
+			response, err := call[dto.PaginatedResponse[T]](ctx, c, http.MethodGet, query, nil, path...)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range response.Data {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if len(response.Data) == 0 || response.Page*response.Limit >= response.Count {
+				return
+			}
+
+			page = response.Page + 1
+		}
+	}
+}
+
+// send the request, retrying it while it fails in a way that's safe to
+// retry. Error responses are returned as *Error, the caller closes the body
+// of the others.
+func (c *Client) send(ctx context.Context, method string, query url.Values, body any, header http.Header, path ...string) (*http.Response, error) {
+	endpoint := c.baseUrl.JoinPath(append([]string{apiVersion}, path...)...)
+	endpoint.RawQuery = query.Encode()
+
+	var encoded []byte
+	if body != nil {
+		var err error
+		if encoded, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("encode %s %s: %w", method, endpoint.Path, err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(encoded))
+		if err != nil {
+			return nil, err
+		}
+
+		for key, values := range header {
+			req.Header[key] = values
+		}
+
+		req.Header.Set("User-Agent", "movies-api-go/"+Version)
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		if c.language != "" {
+			req.Header.Set("Accept-Language", c.language)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			return resp, nil
+		}
+
+		if err == nil {
+			err = problem(resp)
+		}
+
+		if attempt > c.retries || ctx.Err() != nil || !retryable(method, resp, err) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.wait(attempt, resp)):
+		}
+	}
+}
+
+// read the problem of an error response and close it
+func problem(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	apiErr := &Error{}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	if json.Unmarshal(body, &apiErr.Problem) != nil || apiErr.Status == 0 {
+		apiErr.Problem = dto.Problem{Detail: strings.TrimSpace(string(body))}
+	}
+
+	apiErr.Status = resp.StatusCode
+	if apiErr.Title == "" {
+		apiErr.Title = http.StatusText(resp.StatusCode)
+	}
+
+	return apiErr
+}
+
+// rate limited requests weren't handled so they're always retried, other
+// failures only when repeating the request does no harm
+func retryable(method string, resp *http.Response, err error) bool {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if method == http.MethodPost {
+		return false
+	}
+
+	if resp == nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// wait before the retry after the given attempt, as long as the response
+// asks in Retry-After or else doubled on each attempt with up to half of it
+// as jitter
+func (c *Client) wait(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+
+		if date, err := http.ParseTime(resp.Header.Get("Retry-After")); err == nil {
+			return max(time.Until(date), 0)
+		}
+	}
+
+	wait := c.maxBackoff
+	if attempt < 32 {
+		wait = min(c.backoff<<(attempt-1), c.maxBackoff)
+	}
+
+	return wait/2 + rand.N(wait/2+1)
+}
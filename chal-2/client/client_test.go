@@ -0,0 +1,320 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/client"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/feed"
+	"github.com/sglkc/roketin-be-test/chal-2/middlewares"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/routes"
+)
+
+// serve the movie routes of the real router, with the seeded movies, behind
+// the given wrapper and return a client of them with short backoffs
+func serve(t *testing.T, wrap func(http.Handler) http.Handler) *client.Client {
+	t.Helper()
+
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middlewares.RequestId(), middlewares.Locale(), middlewares.Auth(""))
+	routes.RegisterErrorRoutes(router)
+	routes.RegisterV1Routes(router.Group("/v1"))
+
+	var handler http.Handler = router
+	if wrap != nil {
+		handler = wrap(router)
+	}
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := client.New(srv.URL, client.WithHttpClient(srv.Client()),
+		client.WithRetries(2, time.Millisecond, 5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	return c
+}
+
+func TestGetMovie(t *testing.T) {
+	c := serve(t, nil)
+	ctx := context.Background()
+
+	movie, err := c.GetMovie(ctx, 1, "")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if stored := database.FindMovieById(ctx, 1); movie.Title != stored.Title || len(movie.Credits) != len(stored.Credits) {
+		t.Errorf("expected movie 1 %q, got %q", stored.Title, movie.Title)
+	}
+
+	_, err = c.GetMovie(ctx, 9999, "")
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected a *client.Error, got %v", err)
+	}
+
+	if apiErr.Status != http.StatusNotFound || apiErr.Code != dto.CodeMovieNotFound || apiErr.RequestId == "" {
+		t.Errorf("expected a 404 MOVIE_NOT_FOUND problem with a request ID, got %+v", apiErr.Problem)
+	}
+
+	if _, err := c.GetMovie(ctx, 1, "english"); !client.IsCode(err, dto.CodeInvalidLanguage) {
+		t.Errorf("expected INVALID_LANGUAGE, got %v", err)
+	}
+}
+
+func TestAllMoviesPaginates(t *testing.T) {
+	var requests atomic.Int32
+	c := serve(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			next.ServeHTTP(w, r)
+		})
+	})
+	ctx := context.Background()
+
+	stored := database.FindMovies(ctx)
+	var ids []int
+
+	for movie, err := range c.AllMovies(ctx, client.ListOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("all movies: %v", err)
+		}
+
+		ids = append(ids, movie.Id)
+	}
+
+	if len(ids) != len(stored) {
+		t.Fatalf("expected %d movies, got %d", len(stored), len(ids))
+	}
+
+	for i, movie := range stored {
+		if ids[i] != movie.Id {
+			t.Errorf("expected movie %d at %d, got %d", movie.Id, i, ids[i])
+		}
+	}
+
+	if pages := int32((len(stored) + 1) / 2); requests.Load() != pages {
+		t.Errorf("expected %d pages to be requested, got %d", pages, requests.Load())
+	}
+
+	// stopping early doesn't fetch the next page
+	requests.Store(0)
+	for range c.AllMovies(ctx, client.ListOptions{Limit: 2}) {
+		break
+	}
+
+	if requests.Load() != 1 {
+		t.Errorf("expected one page to be requested, got %d", requests.Load())
+	}
+
+	for movie, err := range c.SearchAllMovies(ctx, client.SearchOptions{YearFrom: 3000}) {
+		t.Errorf("expected no movies from the year 3000, got %q: %v", movie.Title, err)
+	}
+}
+
+func TestCreateUpdateDelete(t *testing.T) {
+	c := serve(t, nil)
+	ctx := context.Background()
+
+	request := dto.MovieRequest{
+		Title:       "Top Gun: Maverick",
+		Description: "After more than thirty years of service, Maverick is still pushing the envelope.",
+		Duration:    131,
+		Artists:     []models.Reference{{Name: "Tom Cruise"}},
+		Genres:      []models.Reference{{Name: "Action"}},
+	}
+
+	created, err := c.CreateMovie(ctx, request)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if created.Id == 0 || len(created.Credits) != 1 || created.Credits[0].Role != models.RoleActor {
+		t.Errorf("expected the created movie with Tom Cruise as an actor, got %+v", created)
+	}
+
+	request.Title = "Top Gun: Maverick (IMAX)"
+	if updated, err := c.UpdateMovie(ctx, created.Id, request); err != nil || updated.Title != request.Title {
+		t.Errorf("expected the title to be updated, got %q: %v", updated.Title, err)
+	}
+
+	if err := c.DeleteMovie(ctx, created.Id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if err := c.DeleteMovie(ctx, created.Id); !client.IsCode(err, dto.CodeMovieNotFound) {
+		t.Errorf("expected MOVIE_NOT_FOUND deleting twice, got %v", err)
+	}
+
+	_, err = c.CreateMovie(ctx, dto.MovieRequest{Description: "No title"})
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != dto.CodeValidationFailed {
+		t.Fatalf("expected VALIDATION_FAILED, got %v", err)
+	}
+
+	fields := map[string]bool{}
+	for _, field := range apiErr.Errors {
+		fields[field.Field] = true
+	}
+
+	if !fields["title"] || !fields["duration"] || !fields["genres"] {
+		t.Errorf("expected title, duration and genres to be invalid, got %+v", apiErr.Errors)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var requests, failures atomic.Int32
+	c := serve(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if failures.Add(-1) >= 0 {
+				w.Header().Set("Retry-After", "0")
+				http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+	ctx := context.Background()
+
+	failures.Store(2)
+	if _, err := c.GetMovie(ctx, 1, ""); err != nil || requests.Load() != 3 {
+		t.Errorf("expected the third attempt to succeed, got %d attempts: %v", requests.Load(), err)
+	}
+
+	requests.Store(0)
+	failures.Store(5)
+	_, err := c.GetMovie(ctx, 1, "")
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusServiceUnavailable || apiErr.Detail != "upstream unavailable" {
+		t.Errorf("expected the last 503 once retries ran out, got %v", err)
+	}
+
+	if requests.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", requests.Load())
+	}
+
+	// creating twice would duplicate the movie
+	requests.Store(0)
+	failures.Store(1)
+	if _, err := c.CreateMovie(ctx, dto.MovieRequest{}); requests.Load() != 1 || err == nil {
+		t.Errorf("expected a failed create not to be retried, got %d attempts", requests.Load())
+	}
+}
+
+func TestContext(t *testing.T) {
+	c := serve(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := c.ListMovies(ctx, client.ListOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to cut the Retry-After wait short, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected to give up at the deadline, took %s", elapsed)
+	}
+}
+
+func TestWatchMoviesResumes(t *testing.T) {
+	// the first stream is cut off to make the client reconnect
+	lastEventIds := make(chan string, 10)
+	var streams atomic.Int32
+
+	c := serve(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/movies/events" {
+				lastEventIds <- r.Header.Get("Last-Event-ID")
+
+				if streams.Add(1) == 1 {
+					ctx, cancel := context.WithTimeout(r.Context(), 200*time.Millisecond)
+					defer cancel()
+					r = r.WithContext(ctx)
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	movie := database.FindMovieById(ctx, 1)
+	publish := func(movieId int) {
+		feed.Publish(models.NewMovieEvent(models.EventMovieUpdated, movieId, movie))
+	}
+
+	// the first event is only there to resume after
+	publish(1)
+	sub, _, first, _ := feed.Resume(0, func(models.Event) bool { return false })
+	sub.Close()
+	publish(2)
+	publish(1)
+
+	next, stop := iter.Pull2(c.WatchMovies(ctx, client.WatchOptions{MovieIds: []int{1}, LastEventId: first}))
+	defer stop()
+	// the stream has to end before the iterator can be stopped
+	defer cancel()
+
+	message, err, _ := next()
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+
+	if message.Seq != first+2 || message.Type != string(models.EventMovieUpdated) || message.Event.MovieId != 1 {
+		t.Errorf("expected the buffered update of movie 1 at %d, got %+v", first+2, message)
+	}
+
+	if got := <-lastEventIds; got != strconv.FormatUint(first, 10) {
+		t.Errorf("expected to resume after %d, got %q", first, got)
+	}
+
+	// published once the first stream is cut off, before the client pulls the
+	// next message and reconnects
+	time.Sleep(300 * time.Millisecond)
+	publish(1)
+
+	message, err, _ = next()
+	if err != nil {
+		t.Fatalf("watch after reconnecting: %v", err)
+	}
+
+	if message.Seq != first+3 || message.Event.Movie.Title != movie.Title {
+		t.Errorf("expected the update at %d after reconnecting, got %+v", first+3, message)
+	}
+
+	if got := <-lastEventIds; got != strconv.FormatUint(first+2, 10) {
+		t.Errorf("expected to reconnect after %d, got %q", first+2, got)
+	}
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+// the message type sent when events were missed, reload the movies before
+// carrying on
+const Resync = "resync"
+
+type WatchOptions struct {
+	// only events about these movies
+	MovieIds []int
+	// only events about movies in these genres
+	GenreIds []int
+	// resume after this event, the Seq of the last message handled
+	LastEventId uint64
+}
+
+func (opts WatchOptions) values() url.Values {
+	query := url.Values{}
+	setString(query, "movie_id", joinIds(opts.MovieIds))
+	setString(query, "genre_id", joinIds(opts.GenreIds))
+
+	return query
+}
+
+func joinIds(ids []int) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.Itoa(id)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// stream movie changes from GET /movies/events until ctx is done. A dropped
+// stream is resumed after the last message, so nothing is missed while the
+// server still buffers it, otherwise a Resync message comes first. The
+// stream ends with an error when it can't be opened, or when it keeps
+// dropping without a message more times in a row than the client retries.
+func (c *Client) WatchMovies(ctx context.Context, opts WatchOptions) iter.Seq2[dto.FeedMessage, error] {
+	return func(yield func(dto.FeedMessage, error) bool) {
+		last := opts.LastEventId
+		drops := 0
+
+		for {
+			header := http.Header{}
+			if last > 0 {
+				header.Set("Last-Event-ID", strconv.FormatUint(last, 10))
+			}
+
+			resp, err := c.send(ctx, http.MethodGet, opts.values(), nil, header, "movies", "events")
+			if err != nil {
+				if ctx.Err() == nil {
+					yield(dto.FeedMessage{}, err)
+				}
+				return
+			}
+
+			received := false
+			ok, err := readEvents(resp, func(message dto.FeedMessage) bool {
+				last = message.Seq
+				received = true
+				return yield(message, nil)
+			})
+			resp.Body.Close()
+
+			if !ok || ctx.Err() != nil {
+				return
+			}
+
+			if received {
+				drops = 0
+			}
+
+			if drops++; drops > c.retries {
+				yield(dto.FeedMessage{}, fmt.Errorf("movies api: event stream dropped: %w", err))
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.wait(drops, nil)):
+			}
+		}
+	}
+}
+
+// read the Server-Sent Events of the response, until it ends or handle
+// returns false
+// https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+func readEvents(resp *http.Response, handle func(dto.FeedMessage) bool) (bool, error) {
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+
+	var id, event string
+	var data strings.Builder
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			if data.Len() > 0 || event != "" {
+				message, err := feedMessage(id, event, data.String())
+				if err != nil {
+					return true, err
+				}
+
+				if !handle(message) {
+					return false, nil
+				}
+			}
+
+			id, event = "", ""
+			data.Reset()
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "id":
+			id = value
+		case "event":
+			event = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return true, err
+	}
+
+	return true, io.ErrUnexpectedEOF
+}
+
+func feedMessage(id, event, data string) (dto.FeedMessage, error) {
+	seq, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return dto.FeedMessage{}, fmt.Errorf("event id %q: %w", id, err)
+	}
+
+	message := dto.FeedMessage{Seq: seq, Type: event}
+	if event == Resync {
+		return message, nil
+	}
+
+	message.Event = &models.Event{}
+	if err := json.Unmarshal([]byte(data), message.Event); err != nil {
+		return dto.FeedMessage{}, fmt.Errorf("event %d: %w", seq, err)
+	}
+
+	return message, nil
+}
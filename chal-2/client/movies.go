@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+// movies matching any of the text fields, narrowed down by the others, see
+// GET /movies/search
+type SearchOptions struct {
+	ListOptions
+	Title       string
+	Description string
+	Artist      string
+	Character   string
+	// only match artists and characters credited with this role
+	Role          models.Role
+	Genre         string
+	YearFrom      int
+	YearTo        int
+	Language      string
+	Country       string
+	Certification string
+}
+
+func (opts SearchOptions) values() url.Values {
+	query := opts.ListOptions.values()
+	setString(query, "title", opts.Title)
+	setString(query, "description", opts.Description)
+	setString(query, "artist", opts.Artist)
+	setString(query, "character", opts.Character)
+	setString(query, "role", string(opts.Role))
+	setString(query, "genre", opts.Genre)
+	setInt(query, "year_from", opts.YearFrom)
+	setInt(query, "year_to", opts.YearTo)
+	setString(query, "language", opts.Language)
+	setString(query, "country", opts.Country)
+	setString(query, "certification", opts.Certification)
+
+	return query
+}
+
+func (c *Client) ListMovies(ctx context.Context, opts ListOptions) (dto.PaginatedResponse[models.Movie], error) {
+	return call[dto.PaginatedResponse[models.Movie]](ctx, c, http.MethodGet, opts.values(), nil, "movies")
+}
+
+// every movie from opts.Page on, a page at a time
+func (c *Client) AllMovies(ctx context.Context, opts ListOptions) iter.Seq2[models.Movie, error] {
+	return all[models.Movie](ctx, c, opts, url.Values{}, "movies")
+}
+
+func (c *Client) SearchMovies(ctx context.Context, opts SearchOptions) (dto.PaginatedResponse[models.Movie], error) {
+	return call[dto.PaginatedResponse[models.Movie]](ctx, c, http.MethodGet, opts.values(), nil, "movies", "search")
+}
+
+// every movie found from opts.Page on, a page at a time
+func (c *Client) SearchAllMovies(ctx context.Context, opts SearchOptions) iter.Seq2[models.Movie, error] {
+	return all[models.Movie](ctx, c, opts.ListOptions, opts.values(), "movies", "search")
+}
+
+// the movie in lang, or the client's language when empty
+func (c *Client) GetMovie(ctx context.Context, id int, lang string) (models.Movie, error) {
+	query := url.Values{}
+	setString(query, "lang", lang)
+
+	return data[models.Movie](ctx, c, http.MethodGet, query, nil, "movies", strconv.Itoa(id))
+}
+
+// create a movie and return it as stored, creating isn't retried unless the
+// request was rate limited
+func (c *Client) CreateMovie(ctx context.Context, movie dto.MovieRequest) (models.Movie, error) {
+	return data[models.Movie](ctx, c, http.MethodPost, nil, movie, "movies")
+}
+
+func (c *Client) UpdateMovie(ctx context.Context, id int, movie dto.MovieRequest) (models.Movie, error) {
+	return data[models.Movie](ctx, c, http.MethodPut, nil, movie, "movies", strconv.Itoa(id))
+}
+
+func (c *Client) DeleteMovie(ctx context.Context, id int) error {
+	_, err := call[dto.BaseResponse](ctx, c, http.MethodDelete, nil, nil, "movies", strconv.Itoa(id))
+	return err
+}
+
+// apply the operations of the batch. A batch that was only partly applied,
+// or not at all, isn't an error: its Failed count and the result of each
+// operation tell what happened.
+func (c *Client) BatchMovies(ctx context.Context, batch dto.BatchRequest) (dto.BatchResponse, error) {
+	return call[dto.BatchResponse](ctx, c, http.MethodPost, nil, batch, "movies", "batch")
+}
+
+func (c *Client) MovieRevisions(ctx context.Context, id int, opts ListOptions) (dto.PaginatedResponse[models.Revision], error) {
+	return call[dto.PaginatedResponse[models.Revision]](ctx, c, http.MethodGet, opts.values(), nil, "movies", strconv.Itoa(id), "revisions")
+}
+
+// every revision of the movie from opts.Page on, a page at a time
+func (c *Client) AllMovieRevisions(ctx context.Context, id int, opts ListOptions) iter.Seq2[models.Revision, error] {
+	return all[models.Revision](ctx, c, opts, url.Values{}, "movies", strconv.Itoa(id), "revisions")
+}
+
+// the changes from the revision to revision to, or to the current movie
+// when to is 0
+func (c *Client) DiffMovieRevision(ctx context.Context, id, revision, to int) ([]models.FieldChange, error) {
+	query := url.Values{}
+	setInt(query, "to", to)
+
+	return data[[]models.FieldChange](ctx, c, http.MethodGet, query, nil,
+		"movies", strconv.Itoa(id), "revisions", strconv.Itoa(revision), "diff")
+}
+
+// restore the movie as it was at the revision, which is itself a new
+// revision
+func (c *Client) RestoreMovieRevision(ctx context.Context, id, revision int) (models.Movie, error) {
+	return data[models.Movie](ctx, c, http.MethodPost, nil, nil,
+		"movies", strconv.Itoa(id), "revisions", strconv.Itoa(revision), "restore")
+}
+
+func (c *Client) MovieTranslations(ctx context.Context, id int) (models.Translations, error) {
+	return data[models.Translations](ctx, c, http.MethodGet, nil, nil, "movies", strconv.Itoa(id), "translations")
+}
+
+// add or edit the translation of the movie in lang
+func (c *Client) PutMovieTranslation(ctx context.Context, id int, lang string, translation models.Translation) (models.Translation, error) {
+	return data[models.Translation](ctx, c, http.MethodPut, nil, translation, "movies", strconv.Itoa(id), "translations", lang)
+}
+
+func (c *Client) DeleteMovieTranslation(ctx context.Context, id int, lang string) error {
+	_, err := call[dto.BaseResponse](ctx, c, http.MethodDelete, nil, nil, "movies", strconv.Itoa(id), "translations", lang)
+	return err
+}
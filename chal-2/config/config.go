@@ -0,0 +1,503 @@
+// Package config loads the server settings. Each setting is resolved with the
+// following precedence, highest first:
+//
+//  1. command line flags, e.g. --addr=:9090
+//  2. environment variables, e.g. ADDR=:9090
+//  3. the YAML config file given by --config or CONFIG_FILE
+//  4. built-in defaults
+package config
+
+import (
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Addr          string `yaml:"addr"`
+	GinMode       string `yaml:"gin_mode"`
+	LogLevel      string `yaml:"log_level"`
+	TraceExporter string `yaml:"trace_exporter"`
+
+	Server struct {
+		ReadTimeout     time.Duration `yaml:"read_timeout"`
+		WriteTimeout    time.Duration `yaml:"write_timeout"`
+		IdleTimeout     time.Duration `yaml:"idle_timeout"`
+		ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	} `yaml:"server"`
+
+	// the routes without the /v1 prefix are deprecated since deprecation and
+	// may be removed after sunset, a zero sunset isn't announced. They're
+	// deprecated since the release of /v1 unless told otherwise.
+	UnversionedRoutes struct {
+		Deprecation time.Time `yaml:"deprecation"`
+		Sunset      time.Time `yaml:"sunset"`
+	} `yaml:"unversioned_routes"`
+
+	// the gRPC API listens separately when set, with the TLS files and auth
+	// secret of the HTTP server
+	Grpc struct {
+		Addr string `yaml:"addr"`
+	} `yaml:"grpc"`
+
+	Tls struct {
+		CertFile string `yaml:"cert_file"`
+		KeyFile  string `yaml:"key_file"`
+	} `yaml:"tls"`
+
+	Storage struct {
+		Backend string `yaml:"backend"`
+		DSN     string `yaml:"dsn"`
+	} `yaml:"storage"`
+
+	Pagination struct {
+		DefaultLimit int `yaml:"default_limit"`
+		MaxLimit     int `yaml:"max_limit"`
+	} `yaml:"pagination"`
+
+	Batch struct {
+		MaxSize int `yaml:"max_size"`
+	} `yaml:"batch"`
+
+	Cors struct {
+		AllowedOrigins []string `yaml:"allowed_origins"`
+	} `yaml:"cors"`
+
+	Auth struct {
+		Secret string `yaml:"secret"`
+	} `yaml:"auth"`
+
+	Webhooks struct {
+		Workers        int           `yaml:"workers"`
+		MaxAttempts    int           `yaml:"max_attempts"`
+		InitialBackoff time.Duration `yaml:"initial_backoff"`
+		MaxBackoff     time.Duration `yaml:"max_backoff"`
+		Timeout        time.Duration `yaml:"timeout"`
+		AllowedHosts   []string      `yaml:"allowed_hosts"`
+	} `yaml:"webhooks"`
+
+	Events struct {
+		Bus         string        `yaml:"bus"`
+		NatsUrl     string        `yaml:"nats_url"`
+		NatsSubject string        `yaml:"nats_subject"`
+		BufferSize  int           `yaml:"buffer_size"`
+		Heartbeat   time.Duration `yaml:"heartbeat"`
+	} `yaml:"events"`
+}
+
+// the date /v1 was released, the unversioned routes are deprecated since
+var V1Release = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// setting maps a flag and an environment variable to a config field
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	apply func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{"addr", "ADDR", "listen address, host:port", func(c *Config, v string) error {
+		c.Addr = v
+		return nil
+	}},
+	{"gin-mode", "GIN_MODE", "gin mode: debug, release or test", func(c *Config, v string) error {
+		c.GinMode = v
+		return nil
+	}},
+	{"log-level", "LOG_LEVEL", "log level: debug, info, warn or error", func(c *Config, v string) error {
+		c.LogLevel = v
+		return nil
+	}},
+	{"trace-exporter", "OTEL_TRACES_EXPORTER", "trace exporter: none, stdout or otlp", func(c *Config, v string) error {
+		c.TraceExporter = v
+		return nil
+	}},
+	{"read-timeout", "SERVER_READ_TIMEOUT", "maximum duration for reading a request", func(c *Config, v string) (err error) {
+		c.Server.ReadTimeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"write-timeout", "SERVER_WRITE_TIMEOUT", "maximum duration for writing a response", func(c *Config, v string) (err error) {
+		c.Server.WriteTimeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"idle-timeout", "SERVER_IDLE_TIMEOUT", "how long keep-alive connections stay open", func(c *Config, v string) (err error) {
+		c.Server.IdleTimeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"shutdown-timeout", "SERVER_SHUTDOWN_TIMEOUT", "how long in-flight requests may drain on shutdown", func(c *Config, v string) (err error) {
+		c.Server.ShutdownTimeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"unversioned-deprecation", "UNVERSIONED_DEPRECATION", "date the routes without /v1 were deprecated, YYYY-MM-DD", func(c *Config, v string) (err error) {
+		c.UnversionedRoutes.Deprecation, err = parseDate(v)
+		return err
+	}},
+	{"unversioned-sunset", "UNVERSIONED_SUNSET", "date the routes without /v1 may be removed, YYYY-MM-DD", func(c *Config, v string) (err error) {
+		c.UnversionedRoutes.Sunset, err = parseDate(v)
+		return err
+	}},
+	{"grpc-addr", "GRPC_ADDR", "gRPC listen address, host:port, empty disables gRPC", func(c *Config, v string) error {
+		c.Grpc.Addr = v
+		return nil
+	}},
+	{"tls-cert-file", "TLS_CERT_FILE", "TLS certificate file, enables HTTPS with the key file", func(c *Config, v string) error {
+		c.Tls.CertFile = v
+		return nil
+	}},
+	{"tls-key-file", "TLS_KEY_FILE", "TLS private key file", func(c *Config, v string) error {
+		c.Tls.KeyFile = v
+		return nil
+	}},
+	{"storage-backend", "STORAGE_BACKEND", "storage backend: memory or file", func(c *Config, v string) error {
+		c.Storage.Backend = v
+		return nil
+	}},
+	{"storage-dsn", "STORAGE_DSN", "storage DSN, the JSON file path for the file backend", func(c *Config, v string) error {
+		c.Storage.DSN = v
+		return nil
+	}},
+	{"pagination-default-limit", "PAGINATION_DEFAULT_LIMIT", "page size when no limit is given", func(c *Config, v string) (err error) {
+		c.Pagination.DefaultLimit, err = strconv.Atoi(v)
+		return err
+	}},
+	{"pagination-max-limit", "PAGINATION_MAX_LIMIT", "largest page size a client can request", func(c *Config, v string) (err error) {
+		c.Pagination.MaxLimit, err = strconv.Atoi(v)
+		return err
+	}},
+	{"cors-allowed-origins", "CORS_ALLOWED_ORIGINS", "comma separated CORS origins, * allows any", func(c *Config, v string) error {
+		c.Cors.AllowedOrigins = splitList(v)
+		return nil
+	}},
+	{"batch-max-size", "BATCH_MAX_SIZE", "most operations a movie batch may have", func(c *Config, v string) (err error) {
+		c.Batch.MaxSize, err = strconv.Atoi(v)
+		return err
+	}},
+	{"auth-secret", "AUTH_SECRET", "secret used to sign and verify auth tokens", func(c *Config, v string) error {
+		c.Auth.Secret = v
+		return nil
+	}},
+	{"webhook-workers", "WEBHOOK_WORKERS", "number of concurrent webhook deliveries", func(c *Config, v string) (err error) {
+		c.Webhooks.Workers, err = strconv.Atoi(v)
+		return err
+	}},
+	{"webhook-max-attempts", "WEBHOOK_MAX_ATTEMPTS", "delivery attempts before a webhook event is dead-lettered", func(c *Config, v string) (err error) {
+		c.Webhooks.MaxAttempts, err = strconv.Atoi(v)
+		return err
+	}},
+	{"webhook-initial-backoff", "WEBHOOK_INITIAL_BACKOFF", "wait before the first webhook retry, doubled on each retry", func(c *Config, v string) (err error) {
+		c.Webhooks.InitialBackoff, err = time.ParseDuration(v)
+		return err
+	}},
+	{"webhook-max-backoff", "WEBHOOK_MAX_BACKOFF", "longest wait between webhook retries", func(c *Config, v string) (err error) {
+		c.Webhooks.MaxBackoff, err = time.ParseDuration(v)
+		return err
+	}},
+	{"webhook-timeout", "WEBHOOK_TIMEOUT", "timeout of a single webhook delivery", func(c *Config, v string) (err error) {
+		c.Webhooks.Timeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"webhook-allowed-hosts", "WEBHOOK_ALLOWED_HOSTS", "comma separated private hosts webhooks may deliver to", func(c *Config, v string) error {
+		c.Webhooks.AllowedHosts = splitList(v)
+		return nil
+	}},
+	{"event-bus", "EVENT_BUS", "event bus: channel or nats", func(c *Config, v string) error {
+		c.Events.Bus = v
+		return nil
+	}},
+	{"nats-url", "NATS_URL", "NATS server URL for the nats event bus", func(c *Config, v string) error {
+		c.Events.NatsUrl = v
+		return nil
+	}},
+	{"nats-subject", "NATS_SUBJECT", "subject prefix movie events are published under", func(c *Config, v string) error {
+		c.Events.NatsSubject = v
+		return nil
+	}},
+	{"event-buffer-size", "EVENT_BUFFER_SIZE", "movie events kept for change feed clients to resume from", func(c *Config, v string) (err error) {
+		c.Events.BufferSize, err = strconv.Atoi(v)
+		return err
+	}},
+	{"event-heartbeat", "EVENT_HEARTBEAT", "how often idle change feed streams are kept alive", func(c *Config, v string) (err error) {
+		c.Events.Heartbeat, err = time.ParseDuration(v)
+		return err
+	}},
+}
+
+func Default() *Config {
+	c := &Config{
+		Addr:          ":8080",
+		GinMode:       "debug",
+		LogLevel:      "info",
+		TraceExporter: "none",
+	}
+
+	c.Server.ReadTimeout = 15 * time.Second
+	c.Server.WriteTimeout = 30 * time.Second
+	c.Server.IdleTimeout = 60 * time.Second
+	c.Server.ShutdownTimeout = 20 * time.Second
+	c.UnversionedRoutes.Deprecation = V1Release
+	c.Storage.Backend = "memory"
+	c.Pagination.DefaultLimit = 10
+	c.Pagination.MaxLimit = 100
+	c.Batch.MaxSize = 100
+	c.Webhooks.Workers = 4
+	c.Webhooks.MaxAttempts = 6
+	c.Webhooks.InitialBackoff = time.Second
+	c.Webhooks.MaxBackoff = 5 * time.Minute
+	c.Webhooks.Timeout = 10 * time.Second
+	c.Events.Bus = "channel"
+	c.Events.NatsUrl = "nats://127.0.0.1:4222"
+	c.Events.NatsSubject = "movies.events"
+	c.Events.BufferSize = 1000
+	c.Events.Heartbeat = 15 * time.Second
+
+	return c
+}
+
+// load the config from args (without the program name), the environment and
+// the optional config file, then validate it. flag.ErrHelp is returned as is
+// when --help is given.
+func Load(args []string, stderr io.Writer) (*Config, error) {
+	fs := flag.NewFlagSet("movies-api", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file (env CONFIG_FILE)")
+	flagValues := map[string]*string{}
+
+	for _, s := range settings {
+		flagValues[s.flag] = fs.String(s.flag, "", fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	c := Default()
+
+	if *configFile != "" {
+		if err := c.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(s.env); ok {
+			if err := s.apply(c, value); err != nil {
+				errs = append(errs, fmt.Errorf("env %s: invalid value %q: %w", s.env, value, err))
+			}
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name {
+				if err := s.apply(c, *flagValues[f.Name]); err != nil {
+					errs = append(errs, fmt.Errorf("flag --%s: invalid value %q: %w", f.Name, f.Value, err))
+				}
+			}
+		}
+	})
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(c); err != nil && err != io.EOF {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// collect every problem instead of stopping at the first one, so a broken
+// deployment can be fixed in one go
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, port, err := net.SplitHostPort(c.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("addr %q: must be host:port", c.Addr))
+	} else if p, err := strconv.Atoi(port); err != nil || p < 0 || p > 65535 {
+		errs = append(errs, fmt.Errorf("addr %q: invalid port", c.Addr))
+	}
+
+	if c.UnversionedRoutes.Deprecation.IsZero() && !c.UnversionedRoutes.Sunset.IsZero() {
+		errs = append(errs, errors.New("unversioned_routes.sunset: requires a deprecation"))
+	} else if !c.UnversionedRoutes.Sunset.IsZero() && !c.UnversionedRoutes.Sunset.After(c.UnversionedRoutes.Deprecation) {
+		errs = append(errs, errors.New("unversioned_routes.sunset: must be after the deprecation"))
+	}
+
+	if c.Grpc.Addr != "" {
+		if _, port, err := net.SplitHostPort(c.Grpc.Addr); err != nil {
+			errs = append(errs, fmt.Errorf("grpc.addr %q: must be host:port", c.Grpc.Addr))
+		} else if p, err := strconv.Atoi(port); err != nil || p < 0 || p > 65535 {
+			errs = append(errs, fmt.Errorf("grpc.addr %q: invalid port", c.Grpc.Addr))
+		} else if c.Grpc.Addr == c.Addr {
+			errs = append(errs, fmt.Errorf("grpc.addr %q: must differ from addr", c.Grpc.Addr))
+		}
+	}
+
+	if !slices.Contains([]string{"debug", "release", "test"}, c.GinMode) {
+		errs = append(errs, fmt.Errorf("gin_mode %q: must be debug, release or test", c.GinMode))
+	}
+
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, c.LogLevel) {
+		errs = append(errs, fmt.Errorf("log_level %q: must be debug, info, warn or error", c.LogLevel))
+	}
+
+	if !slices.Contains([]string{"none", "stdout", "otlp"}, c.TraceExporter) {
+		errs = append(errs, fmt.Errorf("trace_exporter %q: must be none, stdout or otlp", c.TraceExporter))
+	}
+
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+	}
+
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s %s: must be positive", timeout.name, timeout.value))
+		}
+	}
+
+	if (c.Tls.CertFile == "") != (c.Tls.KeyFile == "") {
+		errs = append(errs, errors.New("tls: cert_file and key_file must be set together"))
+	}
+
+	if c.Tls.CertFile != "" && c.Tls.KeyFile != "" {
+		if _, err := tls.LoadX509KeyPair(c.Tls.CertFile, c.Tls.KeyFile); err != nil {
+			errs = append(errs, fmt.Errorf("tls: %w", err))
+		}
+	}
+
+	switch c.Storage.Backend {
+	case "memory":
+	case "file":
+		if c.Storage.DSN == "" {
+			errs = append(errs, errors.New("storage.dsn: required for the file backend"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.backend %q: must be memory or file", c.Storage.Backend))
+	}
+
+	if c.Pagination.DefaultLimit < 1 {
+		errs = append(errs, fmt.Errorf("pagination.default_limit %d: must be at least 1", c.Pagination.DefaultLimit))
+	}
+
+	if c.Pagination.MaxLimit < c.Pagination.DefaultLimit {
+		errs = append(errs, fmt.Errorf("pagination.max_limit %d: must not be less than default_limit", c.Pagination.MaxLimit))
+	}
+
+	if c.Batch.MaxSize < 1 {
+		errs = append(errs, fmt.Errorf("batch.max_size %d: must be at least 1", c.Batch.MaxSize))
+	}
+
+	for _, origin := range c.Cors.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			errs = append(errs, fmt.Errorf("cors.allowed_origins %q: must be * or scheme://host[:port]", origin))
+		}
+	}
+
+	if c.Auth.Secret != "" && len(c.Auth.Secret) < 32 {
+		errs = append(errs, errors.New("auth.secret: must be at least 32 characters"))
+	}
+
+	if c.Webhooks.Workers < 1 {
+		errs = append(errs, fmt.Errorf("webhooks.workers %d: must be at least 1", c.Webhooks.Workers))
+	}
+
+	if c.Webhooks.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("webhooks.max_attempts %d: must be at least 1", c.Webhooks.MaxAttempts))
+	}
+
+	if c.Webhooks.InitialBackoff <= 0 || c.Webhooks.MaxBackoff < c.Webhooks.InitialBackoff {
+		errs = append(errs, errors.New("webhooks.initial_backoff: must be positive and not above max_backoff"))
+	}
+
+	if c.Webhooks.Timeout <= 0 {
+		errs = append(errs, errors.New("webhooks.timeout: must be positive"))
+	}
+
+	switch c.Events.Bus {
+	case "channel":
+	case "nats":
+		if c.Events.NatsUrl == "" {
+			errs = append(errs, errors.New("events.nats_url: required for the nats bus"))
+		}
+
+		if c.Events.NatsSubject == "" {
+			errs = append(errs, errors.New("events.nats_subject: required for the nats bus"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("events.bus %q: must be channel or nats", c.Events.Bus))
+	}
+
+	if c.Events.BufferSize < 1 {
+		errs = append(errs, fmt.Errorf("events.buffer_size %d: must be at least 1", c.Events.BufferSize))
+	}
+
+	if c.Events.Heartbeat <= 0 {
+		errs = append(errs, errors.New("events.heartbeat: must be positive"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// a date, or a date and time with a time zone
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+func splitList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
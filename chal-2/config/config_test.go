@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// write a config file into a temporary directory and return its path
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "addr: :1111\nbatch:\n  max_size: 10\n")
+
+	for _, test := range []struct {
+		name         string
+		args         []string
+		env          map[string]string
+		addr         string
+		batchMaxSize int
+	}{
+		{"defaults", nil, nil, ":8080", 100},
+		{"file over defaults", []string{"--config", file}, nil, ":1111", 10},
+		{"config file from the environment", nil, map[string]string{"CONFIG_FILE": file}, ":1111", 10},
+		{"env over file", []string{"--config", file}, map[string]string{"ADDR": ":2222"}, ":2222", 10},
+		{"flag over env", []string{"--config", file, "--addr", ":3333"}, map[string]string{"ADDR": ":2222", "BATCH_MAX_SIZE": "20"}, ":3333", 20},
+		{"flag over everything", []string{"--config", file, "--batch-max-size", "30"}, map[string]string{"BATCH_MAX_SIZE": "20"}, ":1111", 30},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			c, err := Load(test.args, io.Discard)
+			if err != nil {
+				t.Fatalf("failed to load: %v", err)
+			}
+
+			if c.Addr != test.addr || c.Batch.MaxSize != test.batchMaxSize {
+				t.Errorf("expected addr %s and batch size %d, got %s and %d", test.addr, test.batchMaxSize, c.Addr, c.Batch.MaxSize)
+			}
+		})
+	}
+}
+
+func TestLoadInvalidValues(t *testing.T) {
+	t.Setenv("SERVER_READ_TIMEOUT", "soon")
+
+	_, err := Load([]string{"--batch-max-size", "many"}, io.Discard)
+	if err == nil {
+		t.Fatal("expected invalid values to fail")
+	}
+
+	// the parse errors are kept
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected the flag error to wrap strconv.ErrSyntax, got %v", err)
+	}
+
+	for _, want := range []string{
+		`env SERVER_READ_TIMEOUT: invalid value "soon": time: invalid duration`,
+		`flag --batch-max-size: invalid value "many"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestLoadUnknownFileKey(t *testing.T) {
+	file := writeFile(t, "adr: :1111\n")
+
+	if _, err := Load([]string{"--config", file}, io.Discard); err == nil || !strings.Contains(err.Error(), "adr") {
+		t.Errorf("expected the unknown key to be reported, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		change func(c *Config)
+		want   string
+	}{
+		{"defaults", func(*Config) {}, ""},
+		{"addr without port", func(c *Config) { c.Addr = "localhost" }, "addr \"localhost\": must be host:port"},
+		{"addr with invalid port", func(c *Config) { c.Addr = ":99999" }, "addr \":99999\": invalid port"},
+		{"grpc on the http addr", func(c *Config) { c.Grpc.Addr = c.Addr }, "grpc.addr \":8080\": must differ from addr"},
+		{"sunset before deprecation", func(c *Config) {
+			c.UnversionedRoutes.Sunset = c.UnversionedRoutes.Deprecation.AddDate(0, 0, -1)
+		}, "unversioned_routes.sunset: must be after the deprecation"},
+		{"sunset without deprecation", func(c *Config) {
+			c.UnversionedRoutes.Deprecation = time.Time{}
+			c.UnversionedRoutes.Sunset = V1Release
+		}, "unversioned_routes.sunset: requires a deprecation"},
+		{"unknown gin mode", func(c *Config) { c.GinMode = "prod" }, "gin_mode \"prod\""},
+		{"zero timeout", func(c *Config) { c.Server.ShutdownTimeout = 0 }, "server.shutdown_timeout 0s: must be positive"},
+		{"cert without key", func(c *Config) { c.Tls.CertFile = "cert.pem" }, "tls: cert_file and key_file must be set together"},
+		{"file backend without dsn", func(c *Config) { c.Storage.Backend = "file" }, "storage.dsn: required for the file backend"},
+		{"max limit under default", func(c *Config) { c.Pagination.MaxLimit = 5 }, "pagination.max_limit 5"},
+		{"origin with a path", func(c *Config) { c.Cors.AllowedOrigins = []string{"https://example.com/app"} }, "cors.allowed_origins"},
+		{"short secret", func(c *Config) { c.Auth.Secret = "secret" }, "auth.secret: must be at least 32 characters"},
+		{"backoff above max", func(c *Config) { c.Webhooks.InitialBackoff = time.Hour }, "webhooks.initial_backoff"},
+		{"nats without url", func(c *Config) {
+			c.Events.Bus = "nats"
+			c.Events.NatsUrl = ""
+		}, "events.nats_url: required for the nats bus"},
+	} {
+		c := Default()
+		test.change(c)
+
+		err := c.Validate()
+		switch {
+		case test.want == "" && err != nil:
+			t.Errorf("%s: expected no error, got %v", test.name, err)
+		case test.want != "" && (err == nil || !strings.Contains(err.Error(), test.want)):
+			t.Errorf("%s: expected %q, got %v", test.name, test.want, err)
+		}
+	}
+
+	// every problem is reported at once
+	c := Default()
+	c.GinMode = "prod"
+	c.Batch.MaxSize = 0
+
+	if err := c.Validate(); err == nil || len(strings.Split(err.Error(), "\n")) != 2 {
+		t.Errorf("expected both problems to be reported, got %v", err)
+	}
+}
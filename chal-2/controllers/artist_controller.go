@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+var artistResource = &namedResource[models.Artist, models.Artists]{
+	kind:         "artist",
+	title:        "Artist",
+	codeNotFound: dto.CodeArtistNotFound,
+	codeExists:   dto.CodeArtistExists,
+	codeInUse:    dto.CodeArtistInUse,
+	errNotFound:  database.ErrArtistNotFound,
+	errExists:    database.ErrArtistExists,
+	errInUse:     database.ErrArtistInUse,
+	id:           func(artist models.Artist) int { return artist.Id },
+	find:         database.FindArtists,
+	findById:     database.FindArtistById,
+	create:       database.CreateArtist,
+	update:       database.UpdateArtist,
+	delete:       database.DeleteArtist,
+	movies:       database.FindMoviesByArtist,
+}
+
+// @Summary		Get all artists
+// @Description	Get a list of all artists with pagination, optionally filtered by name
+// @Tags			Artists
+// @Param			name	query	string	false	"Artist name to search for"
+// @Param			page	query	int		false	"Page number for pagination"	default(1)
+// @Param			limit	query	int		false	"Number of artists per page"	default(10)
+// @Success		200		{array}	dto.PaginatedResponse[models.Artist]
+// @Router			/artists [get]
+func GetArtists(c *gin.Context) {
+	artistResource.getAll(c)
+}
+
+// @Summary		Get artist
+// @Description	Get artist by ID
+// @Tags			Artists
+// @Param			id	path		int	true	"Artist ID"
+// @Success		200	{object}	dto.DataResponse[models.Artist]
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Router			/artists/{id} [get]
+func GetArtistById(c *gin.Context) {
+	artistResource.getById(c)
+}
+
+// @Summary		Create a new artist
+// @Description	Create a new artist, names must be unique
+// @Tags			Artists
+// @Param			artist	body		models.Artist	true	"Artist object to create"
+// @Success		201		{object}	dto.DataResponse[models.Artist]
+// @Failure		400		{object}	dto.Problem
+// @Failure		409		{object}	dto.Problem
+// @Failure		500		{object}	dto.Problem
+// @Router			/artists [post]
+func PostArtist(c *gin.Context) {
+	artistResource.post(c)
+}
+
+// @Summary		Update an artist
+// @Description	Rename an artist by ID, the change applies to every movie crediting the artist
+// @Tags			Artists
+// @Param			id		path		int				true	"Artist ID"
+// @Param			artist	body		models.Artist	true	"Updated artist object"
+// @Success		200		{object}	dto.DataResponse[models.Artist]
+// @Failure		400		{object}	dto.Problem
+// @Failure		404		{object}	dto.Problem
+// @Failure		409		{object}	dto.Problem
+// @Failure		500		{object}	dto.Problem
+// @Router			/artists/{id} [put]
+func UpdateArtist(c *gin.Context) {
+	artistResource.put(c)
+}
+
+// @Summary		Delete an artist
+// @Description	Delete an artist by ID, artists still credited in a movie can't be deleted
+// @Tags			Artists
+// @Param			id	path		int	true	"Artist ID"
+// @Success		200	{object}	dto.BaseResponse
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Failure		409	{object}	dto.Problem
+// @Failure		500	{object}	dto.Problem
+// @Router			/artists/{id} [delete]
+func DeleteArtist(c *gin.Context) {
+	artistResource.remove(c)
+}
+
+// @Summary		Get artist filmography
+// @Description	Get the movies crediting an artist with pagination, each with the roles the artist is credited for
+// @Tags			Artists
+// @Param			id		path	int		true	"Artist ID"
+// @Param			sort	query	string	false	"Sort by field, prefix with - for descending order"	Enums(id, -id, title, -title, release_date, -release_date)
+// @Param			page	query	int		false	"Page number for pagination"	default(1)
+// @Param			limit	query	int		false	"Number of movies per page"		default(10)
+// @Param			lang	query	string	false	"Language to read titles, descriptions and taglines in, defaults to Accept-Language then the original language"
+// @Success		200		{object}	dto.PaginatedResponse[dto.ArtistMovie]
+// @Failure		400		{object}	dto.Problem
+// @Failure		404		{object}	dto.Problem
+// @Router			/artists/{id}/movies [get]
+func GetArtistMovies(c *gin.Context) {
+	id, movies, ok := artistResource.moviesPage(c)
+	if !ok {
+		return
+	}
+
+	filmography := make([]dto.ArtistMovie, len(movies.Data))
+	for i, movie := range movies.Data {
+		filmography[i] = dto.ArtistMovie{Movie: movie, Roles: models.Credits{}}
+
+		for _, credit := range movie.Credits {
+			if credit.ArtistId == id {
+				filmography[i].Roles = append(filmography[i].Roles, credit)
+			}
+		}
+	}
+
+	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[dto.ArtistMovie]{
+		BaseResponse: movies.BaseResponse,
+		Data:         filmography,
+		Page:         movies.Page,
+		Limit:        movies.Limit,
+		Count:        movies.Count,
+	})
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/metrics"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// @Summary		Get audit log
+// @Description	Get the movie mutations with who made them and the changed fields, newest first with pagination
+// @Tags			Audit
+// @Param			movie_id	query	int		false	"Only entries about this movie"
+// @Param			actor		query	string	false	"Only entries made by this actor"
+// @Param			from		query	string	false	"Only entries at or after this time (RFC 3339)"	example(2025-01-01T00:00:00Z)
+// @Param			to			query	string	false	"Only entries at or before this time (RFC 3339)"	example(2025-12-31T23:59:59Z)
+// @Param			page		query	int		false	"Page number for pagination"	default(1)
+// @Param			limit		query	int		false	"Number of entries per page"	default(10)
+// @Success		200			{object}	dto.PaginatedResponse[models.AuditEntry]
+// @Failure		400			{object}	dto.Problem
+// @Failure		401			{object}	dto.Problem
+// @Router			/audit [get]
+func GetAuditEntries(c *gin.Context) {
+	filter := database.AuditFilter{
+		Actor: c.Query("actor"),
+	}
+
+	if movieId := c.Query("movie_id"); movieId != "" {
+		id, err := strconv.Atoi(movieId)
+		if err != nil {
+			utils.Problem(c, dto.CodeInvalidQuery, "%s must be an integer", "movie_id")
+			return
+		}
+
+		filter.MovieId = id
+	}
+
+	for param, bound := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			utils.Problem(c, dto.CodeInvalidQuery, "%s must be an RFC 3339 time", param)
+			return
+		}
+
+		*bound = parsed
+	}
+
+	entries := database.FindAuditEntries(c.Request.Context(), filter)
+	data, page, limit := utils.Paginate(c, entries)
+	metrics.PaginationLimit.Observe(float64(limit))
+
+	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[models.AuditEntry]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Audit entries found"),
+			Success: true,
+		},
+		Data:  data,
+		Page:  page,
+		Limit: limit,
+		Count: len(entries),
+	})
+}
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// set from the config
+var MaxBatchSize = 100
+
+// @Summary		Create, update and delete movies in a batch
+// @Description	Apply a list of create, update and delete operations in order. With all_or_nothing (the default) every operation is applied or, when one fails, none is. With best_effort each operation is applied on its own.
+// @Description	Every operation gets a result with the status code and error it would have had as a request of its own, operations that weren't applied because another one failed have status 424.
+// @Tags			Movies
+// @Param			batch	body		dto.BatchRequest	true	"Operations to apply, movies are given like in POST /movies"
+// @Success		200		{object}	dto.BatchResponse	"Every operation was applied"
+// @Success		207		{object}	dto.BatchResponse	"Some or all operations failed"
+// @Failure		400		{object}	dto.Problem
+// @Failure		413		{object}	dto.Problem
+// @Failure		500		{object}	dto.Problem
+// @Router			/movies/batch [post]
+func PostMovieBatch(c *gin.Context) {
+	var request dto.BatchRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger(c).Warn("invalid batch body", "error", err)
+		utils.BindProblem(c, err, "Invalid batch body")
+		return
+	}
+
+	if len(request.Operations) > MaxBatchSize {
+		utils.Problem(c, dto.CodeBatchTooLarge, "A batch may have at most %d operations", MaxBatchSize)
+		return
+	}
+
+	if request.Mode == "" {
+		request.Mode = dto.BatchAllOrNothing
+	}
+
+	results := make([]dto.BatchResult, len(request.Operations))
+	movies := make([]models.Movie, len(request.Operations))
+	// the first operation to fail
+	failed := -1
+
+	// validated and resolved up front, so an all or nothing batch with an
+	// invalid operation doesn't even start
+	for i, operation := range request.Operations {
+		results[i] = dto.BatchResult{Index: i, Op: operation.Op, Id: operation.Id}
+
+		if err := binding.Validator.ValidateStruct(operation); err != nil {
+			fail(&results[i], utils.NewBindProblem(c, err, "Invalid operation"))
+			failed = firstFailure(failed, i)
+			continue
+		}
+
+		if operation.Op == dto.BatchDelete {
+			continue
+		}
+
+		movie, err := movieFromRequest(c, *operation.Movie)
+		if code, detail, args, ok := referenceError(err); ok {
+			fail(&results[i], utils.NewProblem(c, code, detail, args...))
+			failed = firstFailure(failed, i)
+			continue
+		}
+
+		if err != nil {
+			utils.Logger(c).Error("failed to resolve batch movie", "index", i, "error", err)
+			fail(&results[i], utils.NewProblem(c, dto.CodeInternalError, "Failed to apply operation"))
+			failed = firstFailure(failed, i)
+			continue
+		}
+
+		movies[i] = movie
+	}
+
+	ctx := c.Request.Context()
+
+	switch {
+	case request.Mode == dto.BatchBestEffort:
+		for i, operation := range request.Operations {
+			if results[i].Error != nil {
+				continue
+			}
+
+			err := database.Transaction(ctx, func(tx database.Tx) error {
+				return applyOperation(tx, operation, movies[i], &results[i])
+			})
+			if err != nil {
+				fail(&results[i], operationProblem(c, operation, err))
+			}
+		}
+	case failed != -1:
+		abort(c, results, failed)
+	default:
+		err := database.Transaction(ctx, func(tx database.Tx) error {
+			for i, operation := range request.Operations {
+				if err := applyOperation(tx, operation, movies[i], &results[i]); err != nil {
+					failed = i
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			fail(&results[failed], operationProblem(c, request.Operations[failed], err))
+			abort(c, results, failed)
+		}
+	}
+
+	response := dto.BatchResponse{Mode: request.Mode, Results: results}
+	for _, result := range results {
+		if result.Error != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+
+	status := http.StatusOK
+	response.Success = true
+	response.Message = utils.T(c, "Batch applied")
+
+	if response.Failed > 0 {
+		status = http.StatusMultiStatus
+		response.Success = false
+		response.Message = utils.T(c, "Batch partially applied")
+
+		if response.Succeeded == 0 {
+			response.Message = utils.T(c, "Batch not applied")
+		}
+	}
+
+	utils.Logger(c).Info("movie batch applied", "mode", request.Mode,
+		"succeeded", response.Succeeded, "failed", response.Failed)
+	c.IndentedJSON(status, response)
+}
+
+// apply the operation in the transaction and fill in its result
+func applyOperation(tx database.Tx, operation dto.BatchOperation, movie models.Movie, result *dto.BatchResult) error {
+	var err error
+
+	switch operation.Op {
+	case dto.BatchCreate:
+		movie, err = tx.CreateMovie(movie)
+		result.Status = http.StatusCreated
+	case dto.BatchUpdate:
+		movie, err = tx.UpdateMovie(operation.Id, movie)
+		result.Status = http.StatusOK
+	case dto.BatchDelete:
+		result.Status = http.StatusOK
+		return tx.DeleteMovie(operation.Id)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	localized := movie.Localize()
+	result.Id = movie.Id
+	result.Movie = &localized
+
+	return nil
+}
+
+// the problem an operation failing with err would have had as a request
+func operationProblem(c *gin.Context, operation dto.BatchOperation, err error) dto.Problem {
+	if code, detail, args, ok := referenceError(err); ok {
+		return utils.NewProblem(c, code, detail, args...)
+	}
+
+	switch {
+	case errors.Is(err, database.ErrMovieNotFound):
+		return utils.NewProblem(c, dto.CodeMovieNotFound, "No movie with ID %d", operation.Id)
+	case errors.Is(err, database.ErrMovieIdExists):
+		return utils.NewProblem(c, dto.CodeMovieExists, "Movie with updated ID already exists")
+	}
+
+	utils.Logger(c).Error("failed to apply batch operation", "op", operation.Op, "id", operation.Id, "error", err)
+	return utils.NewProblem(c, dto.CodeInternalError, "Failed to apply operation")
+}
+
+func firstFailure(failed, i int) int {
+	if failed == -1 {
+		return i
+	}
+
+	return failed
+}
+
+func fail(result *dto.BatchResult, problem dto.Problem) {
+	result.Status = problem.Status
+	result.Movie = nil
+	result.Error = &problem
+}
+
+// mark every other operation of an all or nothing batch as not applied
+// because of the failed one
+func abort(c *gin.Context, results []dto.BatchResult, failed int) {
+	for i := range results {
+		if results[i].Error == nil {
+			if results[i].Op == dto.BatchCreate {
+				results[i].Id = 0
+			}
+
+			fail(&results[i], utils.NewProblem(c, dto.CodeBatchAborted, "Not applied because operation %d failed", failed))
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+)
+
+// post the batch and decode the response into v
+func postBatch(t *testing.T, batch any, v any) int {
+	t.Helper()
+
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/movies/batch", PostMovieBatch)
+
+	body, _ := json.Marshal(batch)
+	req := httptest.NewRequest(http.MethodPost, "/movies/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid response body %s: %v", w.Body, err)
+	}
+
+	return w.Code
+}
+
+// a valid movie body crediting the given artist, by ID or name
+func batchMovie(title string, artist any) map[string]any {
+	return map[string]any{
+		"title":       title,
+		"description": "A movie made up for a batch.",
+		"duration":    100,
+		"artists":     []any{artist},
+		"genres":      []any{1},
+	}
+}
+
+func operation(op dto.BatchOp, id int, movie map[string]any) map[string]any {
+	return map[string]any{"op": op, "id": id, "movie": movie}
+}
+
+func TestBatchAllOrNothingRollsBack(t *testing.T) {
+	ctx := context.Background()
+	if err := database.Migrate(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	before := database.FindMovies(ctx)
+
+	var response dto.BatchResponse
+	code := postBatch(t, map[string]any{
+		"operations": []any{
+			operation(dto.BatchCreate, 0, batchMovie("Created", 1)),
+			operation(dto.BatchUpdate, before[0].Id, batchMovie("Updated", 1)),
+			operation(dto.BatchDelete, before[1].Id, nil),
+			// only fails once applied
+			operation(dto.BatchDelete, 999999, nil),
+		},
+	}, &response)
+
+	if code != http.StatusMultiStatus || response.Success || response.Mode != dto.BatchAllOrNothing ||
+		response.Succeeded != 0 || response.Failed != 4 || len(response.Results) != 4 {
+		t.Fatalf("expected a 207 with every operation failed, got %d %+v", code, response)
+	}
+
+	for i, result := range response.Results[:3] {
+		if result.Status != http.StatusFailedDependency || result.Error == nil || result.Error.Code != dto.CodeBatchAborted || result.Movie != nil {
+			t.Errorf("expected operation %d to be aborted with 424, got %+v", i, result)
+		}
+	}
+
+	if response.Results[0].Id != 0 {
+		t.Errorf("expected the aborted create to have no ID, got %d", response.Results[0].Id)
+	}
+
+	if failed := response.Results[3]; failed.Status != http.StatusNotFound || failed.Error == nil || failed.Error.Code != dto.CodeMovieNotFound {
+		t.Errorf("expected the failing delete to be a 404, got %+v", failed)
+	}
+
+	if after := database.FindMovies(ctx); !reflect.DeepEqual(before, after) {
+		t.Errorf("expected every operation to be rolled back, had %d movies and now %d", len(before), len(after))
+	}
+}
+
+func TestBatchAllOrNothingInvalidOperation(t *testing.T) {
+	ctx := context.Background()
+	if err := database.Migrate(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	before := database.FindMovies(ctx)
+
+	var response dto.BatchResponse
+	code := postBatch(t, map[string]any{
+		"mode": dto.BatchAllOrNothing,
+		"operations": []any{
+			operation(dto.BatchCreate, 0, batchMovie("Created", 1)),
+			operation(dto.BatchCreate, 0, batchMovie("Unknown", "Nobody At All")),
+			operation(dto.BatchUpdate, 0, batchMovie("No ID", 1)),
+		},
+	}, &response)
+
+	if code != http.StatusMultiStatus || response.Failed != 3 {
+		t.Fatalf("expected a 207 with every operation failed, got %d %+v", code, response)
+	}
+
+	for i, want := range []struct {
+		status int
+		code   dto.ErrorCode
+	}{
+		{http.StatusFailedDependency, dto.CodeBatchAborted},
+		{http.StatusBadRequest, dto.CodeUnknownArtist},
+		{http.StatusBadRequest, dto.CodeValidationFailed},
+	} {
+		if result := response.Results[i]; result.Status != want.status || result.Error == nil || result.Error.Code != want.code {
+			t.Errorf("expected operation %d to fail with %d %s, got %+v", i, want.status, want.code, result)
+		}
+	}
+
+	// the failure the others were aborted for is the first one
+	if detail := response.Results[0].Error.Detail; detail != "Not applied because operation 1 failed" {
+		t.Errorf("expected operation 0 to be aborted because of operation 1, got %q", detail)
+	}
+
+	if after := database.FindMovies(ctx); !reflect.DeepEqual(before, after) {
+		t.Errorf("expected nothing to be applied, had %d movies and now %d", len(before), len(after))
+	}
+}
+
+func TestBatchBestEffort(t *testing.T) {
+	var response dto.BatchResponse
+	code := postBatch(t, map[string]any{
+		"mode": dto.BatchBestEffort,
+		"operations": []any{
+			operation(dto.BatchCreate, 0, batchMovie("Best Effort", 1)),
+			operation(dto.BatchDelete, 999999, nil),
+			operation(dto.BatchCreate, 0, batchMovie("Unknown", "Nobody At All")),
+		},
+	}, &response)
+
+	if code != http.StatusMultiStatus || response.Success || response.Succeeded != 1 || response.Failed != 2 {
+		t.Fatalf("expected a 207 with one operation applied, got %d %+v", code, response)
+	}
+
+	created := response.Results[0]
+	if created.Status != http.StatusCreated || created.Error != nil || created.Movie == nil || created.Movie.Title != "Best Effort" {
+		t.Errorf("expected the create to be applied, got %+v", created)
+	}
+	defer database.DeleteMovie(context.Background(), created.Id)
+
+	if movie := database.FindMovieById(context.Background(), created.Id); movie == nil || movie.Title != "Best Effort" {
+		t.Errorf("expected movie %d to be stored, got %+v", created.Id, movie)
+	}
+
+	for i, want := range []struct {
+		status int
+		code   dto.ErrorCode
+	}{
+		{http.StatusNotFound, dto.CodeMovieNotFound},
+		{http.StatusBadRequest, dto.CodeUnknownArtist},
+	} {
+		if result := response.Results[i+1]; result.Status != want.status || result.Error == nil || result.Error.Code != want.code {
+			t.Errorf("expected operation %d to fail with %d %s, got %+v", i+1, want.status, want.code, result)
+		}
+	}
+}
+
+func TestBatchApplied(t *testing.T) {
+	var response dto.BatchResponse
+	code := postBatch(t, map[string]any{
+		"operations": []any{
+			operation(dto.BatchCreate, 0, batchMovie("First", 1)),
+			operation(dto.BatchCreate, 0, batchMovie("Second", 1)),
+		},
+	}, &response)
+
+	if code != http.StatusOK || !response.Success || response.Succeeded != 2 || response.Failed != 0 {
+		t.Fatalf("expected a 200 with every operation applied, got %d %+v", code, response)
+	}
+
+	for _, result := range response.Results {
+		defer database.DeleteMovie(context.Background(), result.Id)
+
+		if result.Status != http.StatusCreated || result.Id == 0 {
+			t.Errorf("expected a created movie, got %+v", result)
+		}
+	}
+}
+
+func TestBatchTooLarge(t *testing.T) {
+	size := MaxBatchSize
+	MaxBatchSize = 2
+	defer func() { MaxBatchSize = size }()
+
+	var problem dto.Problem
+	code := postBatch(t, map[string]any{
+		"operations": []any{
+			operation(dto.BatchDelete, 1, nil),
+			operation(dto.BatchDelete, 2, nil),
+			operation(dto.BatchDelete, 3, nil),
+		},
+	}, &problem)
+
+	if code != http.StatusRequestEntityTooLarge || problem.Code != dto.CodeBatchTooLarge {
+		t.Errorf("expected a 413 for a batch over the limit, got %d %s", code, problem.Code)
+	}
+
+	if movie := database.FindMovieById(context.Background(), 1); movie == nil {
+		t.Error("expected nothing to be applied from a batch over the limit")
+	}
+}
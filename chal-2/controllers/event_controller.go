@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/feed"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+const resync = "resync"
+
+// how long a WebSocket write or pong may take before the client is dropped
+const socketTimeout = 10 * time.Second
+
+// @Summary		Stream movie changes
+// @Description	Stream created, updated and deleted movies as Server-Sent Events, or as JSON messages when the request is a WebSocket upgrade. Events carry the movie after the change, or before it for deletions.
+// @Description	Each event's ID is a sequence number, reconnect with Last-Event-ID (or last_event_id for WebSockets) to resume. When the events after it are no longer buffered a resync event comes first and the client should reload.
+// @Tags			Movies
+// @Produce		text/event-stream
+// @Param			movie_id		query	[]int	false	"Only events about these movies"	collectionFormat(csv)
+// @Param			genre_id		query	[]int	false	"Only events about movies in these genres"	collectionFormat(csv)
+// @Param			last_event_id	query	int		false	"Resume after this event, for clients that can't send the header"
+// @Param			Last-Event-ID	header	int		false	"Resume after this event"
+// @Success		200				{object}	dto.FeedMessage
+// @Failure		400				{object}	dto.Problem
+// @Router			/movies/events [get]
+func GetMovieEvents(c *gin.Context) {
+	filter, ok := eventFilter(c)
+	if !ok {
+		return
+	}
+
+	lastEventId := c.GetHeader("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = c.Query("last_event_id")
+	}
+
+	var after uint64
+	if lastEventId != "" {
+		var err error
+		after, err = strconv.ParseUint(lastEventId, 10, 64)
+		if err != nil {
+			utils.Problem(c, dto.CodeInvalidQuery, "%s must be an integer", "Last-Event-ID")
+			return
+		}
+	}
+
+	var sub *feed.Subscription
+	var backlog []feed.Entry
+	var latest uint64
+	complete := true
+
+	if lastEventId != "" {
+		sub, backlog, latest, complete = feed.Resume(after, filter)
+	} else {
+		sub = feed.Subscribe(filter)
+	}
+	defer sub.Close()
+
+	send := streamEvents
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		send = streamSocket
+	}
+
+	send(c, sub, backlog, latest, complete)
+}
+
+// filter by the movie_id and genre_id params, each a list of IDs given comma
+// separated or repeated
+func eventFilter(c *gin.Context) (func(models.Event) bool, bool) {
+	movieIds, ok := idList(c, "movie_id")
+	if !ok {
+		return nil, false
+	}
+
+	genreIds, ok := idList(c, "genre_id")
+	if !ok {
+		return nil, false
+	}
+
+	return feed.MovieFilter(movieIds, genreIds), true
+}
+
+func idList(c *gin.Context, param string) ([]int, bool) {
+	var ids []int
+
+	for _, values := range c.QueryArray(param) {
+		for _, value := range strings.Split(values, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				utils.Problem(c, dto.CodeInvalidQuery, "%s must be a list of integers", param)
+				return nil, false
+			}
+
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, true
+}
+
+// https://html.spec.whatwg.org/multipage/server-sent-events.html
+func streamEvents(c *gin.Context, sub *feed.Subscription, backlog []feed.Entry, latest uint64, complete bool) {
+	// the server's write timeout would cut the stream off, a stream only ends
+	// when the client leaves or the server shuts down
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		utils.Logger(c).Warn("failed to clear the write deadline of the event stream", "error", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	write := func(format string, args ...any) bool {
+		if _, err := fmt.Fprintf(c.Writer, format, args...); err != nil {
+			return false
+		}
+
+		return rc.Flush() == nil
+	}
+
+	writeEntry := func(entry feed.Entry) bool {
+		data, err := json.Marshal(entry.Event)
+		if err != nil {
+			utils.Logger(c).Error("failed to encode event", "error", err)
+			return true
+		}
+
+		return write("id: %d\nevent: %s\ndata: %s\n\n", entry.Seq, entry.Event.Type, data)
+	}
+
+	// reconnect quickly, resuming is cheap
+	if !write("retry: 3000\n\n") {
+		return
+	}
+
+	if !complete && !write("id: %d\nevent: %s\ndata: {}\n\n", latest, resync) {
+		return
+	}
+
+	for _, entry := range backlog {
+		if !writeEntry(entry) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(feed.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case entry, ok := <-sub.C:
+			if !ok || !writeEntry(entry) {
+				return
+			}
+		case <-heartbeat.C:
+			if !write(": ping\n\n") {
+				return
+			}
+		}
+	}
+}
+
+func streamSocket(c *gin.Context, sub *feed.Subscription, backlog []feed.Entry, latest uint64, complete bool) {
+	upgrader := websocket.Upgrader{
+		// same origin, or one the CORS middleware allowed
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" || origin == c.Writer.Header().Get("Access-Control-Allow-Origin") {
+				return true
+			}
+
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		},
+	}
+
+	// the upgrader answers failed handshakes itself
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		utils.Logger(c).Warn("failed to upgrade to websocket", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	// the client never sends anything but control frames, reading is only
+	// needed to answer pings and notice when it leaves
+	gone := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(feed.Heartbeat + socketTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(feed.Heartbeat + socketTimeout))
+	})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	write := func(message dto.FeedMessage) bool {
+		conn.SetWriteDeadline(time.Now().Add(socketTimeout))
+		return conn.WriteJSON(message) == nil
+	}
+
+	writeEntry := func(entry feed.Entry) bool {
+		return write(dto.FeedMessage{Seq: entry.Seq, Type: string(entry.Event.Type), Event: &entry.Event})
+	}
+
+	if !complete && !write(dto.FeedMessage{Seq: latest, Type: resync}) {
+		return
+	}
+
+	for _, entry := range backlog {
+		if !writeEntry(entry) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(feed.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-gone:
+			return
+		case entry, ok := <-sub.C:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(time.Second))
+				return
+			}
+
+			if !writeEntry(entry) {
+				return
+			}
+		case <-heartbeat.C:
+			if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketTimeout)) != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/feed"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+// set once, the websocket handlers of a finished test may still be reading it
+var shortHeartbeat sync.Once
+
+// serve the change feed with a heartbeat short enough for tests
+func feedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	shortHeartbeat.Do(func() { feed.Heartbeat = 50 * time.Millisecond })
+
+	router := gin.New()
+	router.GET("/movies/events", GetMovieEvents)
+	server := httptest.NewServer(router)
+
+	t.Cleanup(func() {
+		// ends the streams still open so the server can close
+		feed.Close()
+		server.Close()
+	})
+
+	return server
+}
+
+// publish an update of each movie and return the sequence number of the last
+func publishUpdates(movieIds ...int) uint64 {
+	for _, id := range movieIds {
+		feed.Publish(models.NewMovieEvent(models.EventMovieUpdated, id, &models.Movie{Id: id}))
+	}
+
+	sub, _, latest, _ := feed.Resume(0, func(models.Event) bool { return false })
+	sub.Close()
+
+	return latest
+}
+
+// a Server-Sent Events stream read one frame at a time
+type eventStream struct {
+	t      *testing.T
+	reader *bufio.Reader
+}
+
+func openEventStream(t *testing.T, url string, header http.Header) *eventStream {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open the event stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected a 200 event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	return &eventStream{t, bufio.NewReader(resp.Body)}
+}
+
+// the lines of the next frame, up to the blank line ending it
+func (s *eventStream) next() []string {
+	s.t.Helper()
+
+	lines := make(chan []string, 1)
+	go func() {
+		var frame []string
+		for {
+			line, err := s.reader.ReadString('\n')
+			if err != nil {
+				lines <- nil
+				return
+			}
+
+			if line = strings.TrimSuffix(line, "\n"); line == "" {
+				lines <- frame
+				return
+			}
+
+			frame = append(frame, line)
+		}
+	}()
+
+	select {
+	case frame := <-lines:
+		if frame == nil {
+			s.t.Fatal("the event stream ended")
+		}
+
+		return frame
+	case <-time.After(5 * time.Second):
+		s.t.Fatal("timed out waiting for a frame")
+		return nil
+	}
+}
+
+// the next frame that isn't a heartbeat, as an ID, event name and data
+func (s *eventStream) nextEvent() (uint64, string, string) {
+	s.t.Helper()
+
+	for {
+		frame := s.next()
+		if len(frame) == 1 && strings.HasPrefix(frame[0], ":") {
+			continue
+		}
+
+		fields := map[string]string{}
+		for _, line := range frame {
+			name, value, _ := strings.Cut(line, ": ")
+			fields[name] = value
+		}
+
+		id, err := strconv.ParseUint(fields["id"], 10, 64)
+		if err != nil || len(fields) != 3 {
+			s.t.Fatalf("expected an id, event and data, got %q", frame)
+		}
+
+		return id, fields["event"], fields["data"]
+	}
+}
+
+func TestEventStreamResumes(t *testing.T) {
+	server := feedServer(t)
+	after := publishUpdates(1, 2, 1)
+
+	for _, resume := range []struct {
+		query  string
+		header http.Header
+	}{
+		{"", http.Header{"Last-Event-ID": {strconv.FormatUint(after-2, 10)}}},
+		{"&last_event_id=" + strconv.FormatUint(after-2, 10), nil},
+	} {
+		stream := openEventStream(t, server.URL+"/movies/events?movie_id=1"+resume.query, resume.header)
+
+		if frame := stream.next(); len(frame) != 1 || frame[0] != "retry: 3000" {
+			t.Errorf("expected the stream to start with a retry delay, got %q", frame)
+		}
+
+		// of the events after the cursor only the last one is about movie 1
+		id, name, data := stream.nextEvent()
+		if id != after || name != string(models.EventMovieUpdated) {
+			t.Errorf("expected event %d to be resumed, got %d %s", after, id, name)
+		}
+
+		var event models.Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil || event.MovieId != 1 || event.Movie == nil {
+			t.Errorf("expected the data to be the event of movie 1, got %s", data)
+		}
+	}
+}
+
+func TestEventStreamCarriesOnLive(t *testing.T) {
+	server := feedServer(t)
+	after := publishUpdates(1)
+
+	stream := openEventStream(t, server.URL+"/movies/events?movie_id=1",
+		http.Header{"Last-Event-ID": {strconv.FormatUint(after, 10)}})
+	stream.next()
+
+	// a heartbeat comes while idle
+	if frame := stream.next(); len(frame) != 1 || frame[0] != ": ping" {
+		t.Errorf("expected a heartbeat comment, got %q", frame)
+	}
+
+	latest := publishUpdates(2, 1, 1)
+
+	for _, want := range []uint64{latest - 1, latest} {
+		if id, _, _ := stream.nextEvent(); id != want {
+			t.Errorf("expected event %d of movie 1 with nothing skipped or repeated, got %d", want, id)
+		}
+	}
+}
+
+func TestEventStreamResync(t *testing.T) {
+	server := feedServer(t)
+
+	size := feed.BufferSize
+	feed.BufferSize = 2
+	defer func() { feed.BufferSize = size }()
+
+	after := publishUpdates(1)
+	latest := publishUpdates(1, 1, 1)
+
+	stream := openEventStream(t, server.URL+"/movies/events",
+		http.Header{"Last-Event-ID": {strconv.FormatUint(after, 10)}})
+	stream.next()
+
+	if id, name, data := stream.nextEvent(); id != latest || name != "resync" || data != "{}" {
+		t.Errorf("expected a resync to %d, got %d %s %s", latest, id, name, data)
+	}
+
+	// carries on from the resync without replaying what's left in the buffer
+	next := publishUpdates(1)
+	if id, name, _ := stream.nextEvent(); id != next || name != string(models.EventMovieUpdated) {
+		t.Errorf("expected event %d after the resync, got %d %s", next, id, name)
+	}
+}
+
+func TestEventStreamInvalidParams(t *testing.T) {
+	server := feedServer(t)
+
+	for _, test := range []struct {
+		query  string
+		header http.Header
+	}{
+		{"?movie_id=one", nil},
+		{"?genre_id=1,x", nil},
+		{"?last_event_id=-1", nil},
+		{"", http.Header{"Last-Event-ID": {"abc"}}},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/movies/events"+test.query, nil)
+		for name, values := range test.header {
+			req.Header[name] = values
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+
+		var problem dto.Problem
+		json.NewDecoder(resp.Body).Decode(&problem)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest || problem.Code != dto.CodeInvalidQuery {
+			t.Errorf("expected %s %v to be an invalid query, got %d %s", test.query, test.header, resp.StatusCode, problem.Code)
+		}
+	}
+}
+
+func dialFeed(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/movies/events" + query
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("failed to upgrade to a websocket: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+
+	return conn
+}
+
+func readFeedMessage(t *testing.T, conn *websocket.Conn) dto.FeedMessage {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var message dto.FeedMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("failed to read a message: %v", err)
+	}
+
+	return message
+}
+
+func TestEventSocket(t *testing.T) {
+	server := feedServer(t)
+	after := publishUpdates(1, 2, 1)
+
+	conn := dialFeed(t, server, fmt.Sprintf("?movie_id=1&last_event_id=%d", after-2))
+
+	if message := readFeedMessage(t, conn); message.Seq != after || message.Type != string(models.EventMovieUpdated) ||
+		message.Event == nil || message.Event.MovieId != 1 {
+		t.Errorf("expected event %d of movie 1 to be resumed, got %+v", after, message)
+	}
+
+	latest := publishUpdates(2, 1)
+	if message := readFeedMessage(t, conn); message.Seq != latest {
+		t.Errorf("expected event %d live, got %d", latest, message.Seq)
+	}
+
+	// idle sockets are pinged
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(data string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	go conn.ReadMessage()
+
+	select {
+	case <-pinged:
+	case <-time.After(5 * time.Second):
+		t.Error("timed out waiting for a ping")
+	}
+}
+
+func TestEventSocketResync(t *testing.T) {
+	server := feedServer(t)
+
+	size := feed.BufferSize
+	feed.BufferSize = 2
+	defer func() { feed.BufferSize = size }()
+
+	after := publishUpdates(1)
+	latest := publishUpdates(1, 1, 1)
+
+	conn := dialFeed(t, server, fmt.Sprintf("?last_event_id=%d", after))
+
+	if message := readFeedMessage(t, conn); message.Seq != latest || message.Type != "resync" || message.Event != nil {
+		t.Errorf("expected a resync to %d, got %+v", latest, message)
+	}
+}
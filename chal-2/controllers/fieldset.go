@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// JSON names of the movie fields ?fields= can pick, translations are never
+// served with a movie
+var movieFields = jsonFields(reflect.TypeFor[models.Movie](), "translations")
+
+// relations ?expand= can embed in a movie
+var movieExpansions = []string{"artists", "genres"}
+
+// how ?fields= trims the movies of a response and which relations ?expand=
+// embeds, no fields means every field
+// https://jsonapi.org/format/#fetching-sparse-fieldsets
+type movieShape struct {
+	fields []string
+	expand []string
+}
+
+func movieShapeOf(c *gin.Context) (movieShape, bool) {
+	fields, ok := nameList(c, "fields", movieFields)
+	if !ok {
+		return movieShape{}, false
+	}
+
+	expand, ok := nameList(c, "expand", movieExpansions)
+	if !ok {
+		return movieShape{}, false
+	}
+
+	return movieShape{fields, expand}, true
+}
+
+// the comma separated names of the param, each one of known
+func nameList(c *gin.Context, param string, known []string) ([]string, bool) {
+	var names []string
+
+	for _, name := range strings.Split(c.Query(param), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(names, name) {
+			continue
+		}
+
+		if !slices.Contains(known, name) {
+			utils.Problem(c, dto.CodeInvalidQuery, "Unknown %s value %q, expected any of %s", param, name, strings.Join(known, ", "))
+			return nil, false
+		}
+
+		names = append(names, name)
+	}
+
+	return names, true
+}
+
+// the movies as the response should have them, as they are without fields
+// or relations to expand, otherwise as JSON objects with the picked fields
+// and the relations of every movie looked up at once
+func shapeMovies(c *gin.Context, shape movieShape, movies []models.Movie) ([]any, bool) {
+	shaped := make([]any, len(movies))
+
+	if len(shape.fields) == 0 && len(shape.expand) == 0 {
+		for i, movie := range movies {
+			shaped[i] = movie
+		}
+
+		return shaped, true
+	}
+
+	var artists map[int]models.Artist
+	var genres map[int]models.Genre
+
+	if slices.Contains(shape.expand, "artists") {
+		var ids []int
+		for _, movie := range movies {
+			for _, credit := range movie.Credits {
+				ids = append(ids, credit.ArtistId)
+			}
+		}
+
+		artists = database.FindArtistsByIds(c.Request.Context(), ids)
+	}
+
+	if slices.Contains(shape.expand, "genres") {
+		var ids []int
+		for _, movie := range movies {
+			ids = append(ids, movie.GenreIds...)
+		}
+
+		genres = database.FindGenresByIds(c.Request.Context(), ids)
+	}
+
+	for i, movie := range movies {
+		object, err := pickFields(movie, shape.fields)
+		if err != nil {
+			utils.Logger(c).Error("failed to pick movie fields", "movie_id", movie.Id, "error", err)
+			utils.Problem(c, dto.CodeInternalError, "Failed to read movies")
+			return nil, false
+		}
+
+		if artists != nil {
+			// once each, in billing order
+			movieArtists := []models.Artist{}
+			for _, credit := range movie.Credits {
+				artist, ok := artists[credit.ArtistId]
+				if ok && !slices.Contains(movieArtists, artist) {
+					movieArtists = append(movieArtists, artist)
+				}
+			}
+
+			object.set("artists", movieArtists)
+		}
+
+		if genres != nil {
+			movieGenres := []models.Genre{}
+			for _, id := range movie.GenreIds {
+				if genre, ok := genres[id]; ok {
+					movieGenres = append(movieGenres, genre)
+				}
+			}
+
+			object.set("genres", movieGenres)
+		}
+
+		shaped[i] = object
+	}
+
+	return shaped, true
+}
+
+// the movie as a JSON object with only the given fields, or all of them,
+// empty fields are left out like they are from a whole movie
+func pickFields(movie models.Movie, fields []string) (*orderedObject, error) {
+	encoded, err := json.Marshal(movie)
+	if err != nil {
+		return nil, err
+	}
+
+	// read field by field to keep them in the order of the struct
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	object := &orderedObject{}
+	for decoder.More() {
+		name, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if len(fields) == 0 || slices.Contains(fields, name.(string)) {
+			object.set(name.(string), value)
+		}
+	}
+
+	return object, nil
+}
+
+// a JSON object with its members in the order they were set, so picked
+// fields keep the order of the whole model with expansions after them
+type orderedObject struct {
+	names  []string
+	values map[string]any
+}
+
+func (object *orderedObject) set(name string, value any) {
+	if object.values == nil {
+		object.values = map[string]any{}
+	}
+
+	if _, ok := object.values[name]; !ok {
+		object.names = append(object.names, name)
+	}
+
+	object.values[name] = value
+}
+
+func (object *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i, name := range object.names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(object.values[name])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// the JSON names of the fields of the struct type, except the skipped ones
+func jsonFields(t reflect.Type, skip ...string) []string {
+	var names []string
+
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" || slices.Contains(skip, name) {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
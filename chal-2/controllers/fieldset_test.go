@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+)
+
+func movieRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/movies", GetMovies)
+	router.GET("/movies/search", SearchMovie)
+	router.GET("/movies/:id", GetMovieById)
+
+	return router
+}
+
+// the names of the JSON object in the order they appear
+func objectKeys(t *testing.T, raw json.RawMessage) []string {
+	t.Helper()
+
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	if _, err := decoder.Token(); err != nil {
+		t.Fatalf("expected an object, got %s", raw)
+	}
+
+	var keys []string
+	for decoder.More() {
+		key, _ := decoder.Token()
+		keys = append(keys, key.(string))
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			t.Fatalf("failed to decode %s: %v", key, err)
+		}
+	}
+
+	return keys
+}
+
+func TestSparseFieldsets(t *testing.T) {
+	router := movieRouter(t)
+
+	for _, path := range []string{"/movies?", "/movies/search?year_from=1900&", "/movies/1?"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"fields=id,title&expand=artists,genres", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d %s", path, w.Code, w.Body)
+		}
+
+		var body struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: failed to decode body: %v", path, err)
+		}
+
+		movie := body.Data
+		if body.Data[0] == '[' {
+			var movies []json.RawMessage
+			json.Unmarshal(body.Data, &movies)
+			if len(movies) == 0 {
+				t.Fatalf("%s: expected movies", path)
+			}
+			movie = movies[0]
+		}
+
+		keys := strings.Join(objectKeys(t, movie), ",")
+		if keys != "id,title,artists,genres" {
+			t.Errorf("%s: expected id, title, artists and genres in order, got %s", path, keys)
+		}
+	}
+
+	// a whole movie keeps the order of the model too
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/1?expand=genres", nil))
+
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &body)
+
+	keys := objectKeys(t, body.Data)
+	if keys[0] != "id" || keys[1] != "title" || keys[len(keys)-1] != "genres" {
+		t.Errorf("expected the fields of the model then genres, got %v", keys)
+	}
+}
+
+func TestUnknownField(t *testing.T) {
+	router := movieRouter(t)
+
+	for _, path := range []string{"/movies?fields=id,budget", "/movies/search?fields=budget", "/movies/1?expand=studios"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "INVALID_QUERY") {
+			t.Errorf("%s: expected a 400 INVALID_QUERY, got %d %s", path, w.Code, w.Body)
+		}
+	}
+}
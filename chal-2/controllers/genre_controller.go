@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+var genreResource = &namedResource[models.Genre, models.Genres]{
+	kind:         "genre",
+	title:        "Genre",
+	codeNotFound: dto.CodeGenreNotFound,
+	codeExists:   dto.CodeGenreExists,
+	codeInUse:    dto.CodeGenreInUse,
+	errNotFound:  database.ErrGenreNotFound,
+	errExists:    database.ErrGenreExists,
+	errInUse:     database.ErrGenreInUse,
+	id:           func(genre models.Genre) int { return genre.Id },
+	find:         database.FindGenres,
+	findById:     database.FindGenreById,
+	create:       database.CreateGenre,
+	update:       database.UpdateGenre,
+	delete:       database.DeleteGenre,
+	movies:       database.FindMoviesByGenre,
+}
+
+// @Summary		Get all genres
+// @Description	Get a list of all genres with pagination, optionally filtered by name
+// @Tags			Genres
+// @Param			name	query	string	false	"Genre name to search for"
+// @Param			page	query	int		false	"Page number for pagination"	default(1)
+// @Param			limit	query	int		false	"Number of genres per page"		default(10)
+// @Success		200		{array}	dto.PaginatedResponse[models.Genre]
+// @Router			/genres [get]
+func GetGenres(c *gin.Context) {
+	genreResource.getAll(c)
+}
+
+// @Summary		Get genre
+// @Description	Get genre by ID
+// @Tags			Genres
+// @Param			id	path		int	true	"Genre ID"
+// @Success		200	{object}	dto.DataResponse[models.Genre]
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Router			/genres/{id} [get]
+func GetGenreById(c *gin.Context) {
+	genreResource.getById(c)
+}
+
+// @Summary		Create a new genre
+// @Description	Create a new genre, names must be unique
+// @Tags			Genres
+// @Param			genre	body		models.Genre	true	"Genre object to create"
+// @Success		201		{object}	dto.DataResponse[models.Genre]
+// @Failure		400		{object}	dto.Problem
+// @Failure		409		{object}	dto.Problem
+// @Failure		500		{object}	dto.Problem
+// @Router			/genres [post]
+func PostGenre(c *gin.Context) {
+	genreResource.post(c)
+}
+
+// @Summary		Update a genre
+// @Description	Rename a genre by ID, the change applies to every movie in the genre
+// @Tags			Genres
+// @Param			id		path		int				true	"Genre ID"
+// @Param			genre	body		models.Genre	true	"Updated genre object"
+// @Success		200		{object}	dto.DataResponse[models.Genre]
+// @Failure		400		{object}	dto.Problem
+// @Failure		404		{object}	dto.Problem
+// @Failure		409		{object}	dto.Problem
+// @Failure		500		{object}	dto.Problem
+// @Router			/genres/{id} [put]
+func UpdateGenre(c *gin.Context) {
+	genreResource.put(c)
+}
+
+// @Summary		Delete a genre
+// @Description	Delete a genre by ID, genres still used by a movie can't be deleted
+// @Tags			Genres
+// @Param			id	path		int	true	"Genre ID"
+// @Success		200	{object}	dto.BaseResponse
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Failure		409	{object}	dto.Problem
+// @Failure		500	{object}	dto.Problem
+// @Router			/genres/{id} [delete]
+func DeleteGenre(c *gin.Context) {
+	genreResource.remove(c)
+}
+
+// @Summary		Get movies by genre
+// @Description	Get the movies in a genre with pagination
+// @Tags			Genres
+// @Param			id		path	int		true	"Genre ID"
+// @Param			sort	query	string	false	"Sort by field, prefix with - for descending order"	Enums(id, -id, title, -title, release_date, -release_date)
+// @Param			page	query	int		false	"Page number for pagination"	default(1)
+// @Param			limit	query	int		false	"Number of movies per page"		default(10)
+// @Param			lang	query	string	false	"Language to read titles, descriptions and taglines in, defaults to Accept-Language then the original language"
+// @Success		200		{object}	dto.PaginatedResponse[models.Movie]
+// @Failure		400		{object}	dto.Problem
+// @Failure		404		{object}	dto.Problem
+// @Router			/genres/{id}/movies [get]
+func GetGenreMovies(c *gin.Context) {
+	if _, movies, ok := genreResource.moviesPage(c); ok {
+		c.IndentedJSON(http.StatusOK, movies)
+	}
+}
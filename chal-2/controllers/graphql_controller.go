@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dataloader"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// https://github.com/graph-gophers/graphql-go
+//
+//go:embed schema.graphql
+var graphqlSchemaString string
+
+//go:embed graphiql.html
+var graphiqlPage []byte
+
+var graphqlSchema = graphql.MustParseSchema(graphqlSchemaString, &graphqlResolver{},
+	graphql.UseStringDescriptions(),
+	graphql.MaxDepth(10),
+)
+
+// @Summary		Query and mutate the catalogue with GraphQL
+// @Description	Run a GraphQL query or mutation, the schema can be read through introspection or the GraphiQL playground at GET /graphql in debug mode.
+// @Description	Errors have the code, status and invalid fields the REST endpoints would respond with as extensions.
+// @Tags			GraphQL
+// @Param			request	body		dto.GraphqlRequest	true	"GraphQL query, operation name and variables"
+// @Success		200		{object}	dto.GraphqlResponse
+// @Failure		400		{object}	dto.Problem
+// @Router			/graphql [post]
+func PostGraphql(c *gin.Context) {
+	var request dto.GraphqlRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger(c).Warn("invalid graphql body", "error", err)
+		utils.BindProblem(c, err, "Invalid GraphQL request")
+		return
+	}
+
+	state := newGraphqlState(c)
+	ctx := context.WithValue(c.Request.Context(), graphqlStateKey{}, state)
+	result := graphqlSchema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+
+	response := dto.GraphqlResponse{Data: result.Data}
+	for _, err := range result.Errors {
+		graphqlErr := dto.GraphqlError{
+			Message:    err.Message,
+			Path:       err.Path,
+			Extensions: err.Extensions,
+		}
+
+		for _, location := range err.Locations {
+			graphqlErr.Locations = append(graphqlErr.Locations, dto.GraphqlLocation(location))
+		}
+
+		response.Errors = append(response.Errors, graphqlErr)
+	}
+
+	utils.Logger(c).Debug("graphql executed", "operation", request.OperationName, "errors", len(result.Errors),
+		"artist_batches", state.artists.Batches(), "genre_batches", state.genres.Batches())
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// the GraphiQL playground, only served in debug mode
+func GetGraphiql(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", graphiqlPage)
+}
+
+type graphqlStateKey struct{}
+
+// what the resolvers of one request share, the request itself to respond in
+// its locale and the loaders batching artist and genre lookups across movies
+type graphqlState struct {
+	c       *gin.Context
+	artists *dataloader.Loader[int, models.Artist]
+	genres  *dataloader.Loader[int, models.Genre]
+}
+
+func newGraphqlState(c *gin.Context) *graphqlState {
+	return &graphqlState{
+		c:       c,
+		artists: dataloader.New(database.FindArtistsByIds),
+		genres:  dataloader.New(database.FindGenresByIds),
+	}
+}
+
+func graphqlStateFrom(ctx context.Context) *graphqlState {
+	return ctx.Value(graphqlStateKey{}).(*graphqlState)
+}
+
+// a problem reported as a GraphQL error, the message is the detail and the
+// rest of the problem goes in the extensions
+type graphqlError struct {
+	problem dto.Problem
+}
+
+func (err graphqlError) Error() string {
+	if err.problem.Detail != "" {
+		return err.problem.Detail
+	}
+
+	return err.problem.Title
+}
+
+func (err graphqlError) Extensions() map[string]any {
+	extensions := map[string]any{
+		"code":   err.problem.Code,
+		"status": err.problem.Status,
+		"type":   err.problem.Type,
+		"title":  err.problem.Title,
+	}
+
+	if err.problem.RequestId != "" {
+		extensions["request_id"] = err.problem.RequestId
+	}
+
+	if len(err.problem.Errors) > 0 {
+		extensions["errors"] = err.problem.Errors
+	}
+
+	return extensions
+}
+
+func graphqlProblem(ctx context.Context, code dto.ErrorCode, detail string, args ...any) error {
+	return graphqlError{utils.NewProblem(graphqlStateFrom(ctx).c, code, detail, args...)}
+}
+
+// invalid fields are named like the fields of the GraphQL input, e.g.
+// credits[0].original_title becomes credits[0].originalTitle
+func graphqlBindProblem(ctx context.Context, err error, detail string) error {
+	problem := utils.NewBindProblem(graphqlStateFrom(ctx).c, err, detail)
+
+	for i, fieldError := range problem.Errors {
+		parts := strings.Split(fieldError.Field, "_")
+		for j := 1; j < len(parts); j++ {
+			if parts[j] != "" {
+				parts[j] = strings.ToUpper(parts[j][:1]) + parts[j][1:]
+			}
+		}
+
+		problem.Errors[i].Field = strings.Join(parts, "")
+	}
+
+	return graphqlError{problem}
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+)
+
+// run the query the way PostGraphql does and return the state it used
+func execGraphql(t *testing.T, query string) (*graphqlState, []*gqlerrors.QueryError) {
+	t.Helper()
+
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/graphql", nil)
+
+	state := newGraphqlState(c)
+	ctx := context.WithValue(c.Request.Context(), graphqlStateKey{}, state)
+	result := graphqlSchema.Exec(ctx, query, "", nil)
+
+	return state, result.Errors
+}
+
+func TestGraphqlBatchesArtists(t *testing.T) {
+	state, errs := execGraphql(t, `{
+		movies(limit: 10) {
+			items {
+				artists { name }
+				credits { artist { name } }
+				genres { name }
+			}
+		}
+	}`)
+	if len(errs) > 0 {
+		t.Fatalf("query failed: %v", errs)
+	}
+
+	if batches := state.artists.Batches(); batches != 1 {
+		t.Errorf("expected the artists of every movie to be looked up at once, got %d lookups", batches)
+	}
+
+	if batches := state.genres.Batches(); batches != 1 {
+		t.Errorf("expected the genres of every movie to be looked up at once, got %d lookups", batches)
+	}
+}
+
+func TestGraphqlUnknownArtist(t *testing.T) {
+	_, errs := execGraphql(t, `mutation {
+		createMovie(movie: {
+			title: "Top Gun: Maverick"
+			description: "After more than thirty years of service, Maverick is still pushing the envelope."
+			duration: 131
+			artists: ["Nobody At All"]
+			genres: ["Action"]
+		}) { id }
+	}`)
+
+	if len(errs) != 1 {
+		t.Fatalf("expected one error, got %v", errs)
+	}
+
+	if code := errs[0].Extensions["code"]; code != dto.CodeUnknownArtist {
+		t.Errorf("expected an UNKNOWN_ARTIST error, got %v", errs[0])
+	}
+}
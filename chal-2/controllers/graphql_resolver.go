@@ -0,0 +1,502 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/metrics"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+	"github.com/sglkc/roketin-be-test/chal-2/validators"
+)
+
+// resolves the fields of schema.graphql, graphql-go matches fields to methods
+// by name and arguments to struct fields. Int is int32 and optional values
+// are pointers.
+type graphqlResolver struct{}
+
+type movieFilterInput struct {
+	Title         *string
+	Description   *string
+	Artist        *string
+	Character     *string
+	Role          *string
+	Genre         *string
+	YearFrom      *int32
+	YearTo        *int32
+	Language      *string
+	Country       *string
+	Certification *string
+}
+
+func (input *movieFilterInput) filter() database.MovieFilter {
+	if input == nil {
+		return database.MovieFilter{}
+	}
+
+	return database.MovieFilter{
+		Title:         value(input.Title),
+		Description:   value(input.Description),
+		Artist:        value(input.Artist),
+		Character:     value(input.Character),
+		Role:          models.Role(value(input.Role)),
+		Genre:         value(input.Genre),
+		YearFrom:      int(value(input.YearFrom)),
+		YearTo:        int(value(input.YearTo)),
+		Language:      value(input.Language),
+		Country:       value(input.Country),
+		Certification: value(input.Certification),
+	}
+}
+
+type movieInput struct {
+	Id                  *int32
+	Title               string
+	OriginalTitle       *string
+	Tagline             *string
+	Description         string
+	Duration            int32
+	ReleaseDate         *string
+	OriginalLanguage    *string
+	ProductionCountries *[]string
+	AgeCertification    *string
+	Credits             *[]creditInput
+	Artists             *[]graphql.ID
+	Genres              []graphql.ID
+}
+
+type creditInput struct {
+	Artist    graphql.ID
+	Role      string
+	Character *string
+	Order     *int32
+}
+
+// the input as the body of POST /movies, so both are validated the same way
+func (input movieInput) request() dto.MovieRequest {
+	request := dto.MovieRequest{
+		Id:                  int(value(input.Id)),
+		Title:               input.Title,
+		OriginalTitle:       value(input.OriginalTitle),
+		Tagline:             value(input.Tagline),
+		Description:         input.Description,
+		Duration:            int(input.Duration),
+		ReleaseDate:         value(input.ReleaseDate),
+		OriginalLanguage:    value(input.OriginalLanguage),
+		ProductionCountries: value(input.ProductionCountries),
+		AgeCertification:    value(input.AgeCertification),
+		Genres:              references(input.Genres),
+	}
+
+	if input.Artists != nil {
+		request.Artists = references(*input.Artists)
+	}
+
+	if input.Credits != nil {
+		request.Credits = make([]dto.CreditRequest, len(*input.Credits))
+		for i, credit := range *input.Credits {
+			request.Credits[i] = dto.CreditRequest{
+				Artist:    reference(credit.Artist),
+				Role:      models.Role(credit.Role),
+				Character: value(credit.Character),
+				Order:     int(value(credit.Order)),
+			}
+		}
+	}
+
+	return request
+}
+
+// IDs are strings in GraphQL whether written as a number or not, so a
+// numeric one refers to an ID and anything else to a name
+func reference(id graphql.ID) models.Reference {
+	if n, err := strconv.Atoi(string(id)); err == nil {
+		return models.Reference{Id: n}
+	}
+
+	return models.Reference{Name: string(id)}
+}
+
+func references(ids []graphql.ID) []models.Reference {
+	refs := make([]models.Reference, len(ids))
+	for i, id := range ids {
+		refs[i] = reference(id)
+	}
+
+	return refs
+}
+
+func value[T any](pointer *T) T {
+	var zero T
+	if pointer == nil {
+		return zero
+	}
+
+	return *pointer
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// languages to read movie text in, the lang argument wins over
+// Accept-Language like ?lang= does
+func graphqlLanguages(ctx context.Context, lang *string) ([]string, error) {
+	if lang == nil || *lang == "" {
+		return acceptLanguages(graphqlStateFrom(ctx).c), nil
+	}
+
+	if !validators.IsLanguageCode(*lang) {
+		return nil, graphqlProblem(ctx, dto.CodeInvalidLanguage, "Language %q must be an ISO 639-1 code", *lang)
+	}
+
+	return []string{*lang}, nil
+}
+
+func (*graphqlResolver) Movies(ctx context.Context, args struct {
+	Filter *movieFilterInput
+	Sort   *string
+	Page   *int32
+	Limit  *int32
+	Lang   *string
+}) (*moviePageResolver, error) {
+	languages, err := graphqlLanguages(ctx, args.Lang)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.Movies
+	if filter := args.Filter.filter(); filter == (database.MovieFilter{}) {
+		result = database.FindMovies(ctx)
+	} else {
+		result = database.SearchMovies(ctx, filter)
+		metrics.SearchResults.Observe(float64(len(result)))
+	}
+
+	// localized first so titles sort in the language they're shown in
+	localizeMovies(result, languages)
+
+	if err := database.SortMovies(result, value(args.Sort)); err != nil {
+		return nil, graphqlProblem(ctx, dto.CodeInvalidQuery, "Unknown sort %q", value(args.Sort))
+	}
+
+	data, page, limit := utils.PageOf(ctx, result, int(value(args.Page)), int(value(args.Limit)))
+	metrics.PaginationLimit.Observe(float64(limit))
+
+	return &moviePageResolver{
+		items: newMovieResolvers(ctx, data),
+		page:  page,
+		limit: limit,
+		count: len(result),
+	}, nil
+}
+
+func (*graphqlResolver) Movie(ctx context.Context, args struct {
+	Id   int32
+	Lang *string
+}) (*movieResolver, error) {
+	languages, err := graphqlLanguages(ctx, args.Lang)
+	if err != nil {
+		return nil, err
+	}
+
+	movie := database.FindMovieById(ctx, int(args.Id))
+	if movie == nil {
+		return nil, nil
+	}
+
+	return newMovieResolver(ctx, movie.Localize(languages...)), nil
+}
+
+func (*graphqlResolver) Artists(ctx context.Context, args struct{ Name *string }) []*artistResolver {
+	artists := database.FindArtists(ctx, value(args.Name))
+
+	resolvers := make([]*artistResolver, len(artists))
+	for i, artist := range artists {
+		resolvers[i] = &artistResolver{artist}
+	}
+
+	return resolvers
+}
+
+func (*graphqlResolver) Artist(ctx context.Context, args struct{ Id int32 }) *artistResolver {
+	if artist := database.FindArtistById(ctx, int(args.Id)); artist != nil {
+		return &artistResolver{*artist}
+	}
+
+	return nil
+}
+
+func (*graphqlResolver) Genres(ctx context.Context, args struct{ Name *string }) []*genreResolver {
+	genres := database.FindGenres(ctx, value(args.Name))
+
+	resolvers := make([]*genreResolver, len(genres))
+	for i, genre := range genres {
+		resolvers[i] = &genreResolver{genre}
+	}
+
+	return resolvers
+}
+
+func (*graphqlResolver) Genre(ctx context.Context, args struct{ Id int32 }) *genreResolver {
+	if genre := database.FindGenreById(ctx, int(args.Id)); genre != nil {
+		return &genreResolver{*genre}
+	}
+
+	return nil
+}
+
+// validate the input like the body of POST /movies and resolve it into the
+// movie to store
+func graphqlMovie(ctx context.Context, input movieInput) (models.Movie, error) {
+	request := input.request()
+
+	if err := binding.Validator.ValidateStruct(request); err != nil {
+		utils.Logger(graphqlStateFrom(ctx).c).Warn("invalid movie input", "error", err)
+		return models.Movie{}, graphqlBindProblem(ctx, err, "Invalid movie body")
+	}
+
+	movie, err := movieFromRequest(graphqlStateFrom(ctx).c, request)
+	if code, detail, args, ok := referenceError(err); ok {
+		return models.Movie{}, graphqlProblem(ctx, code, detail, args...)
+	}
+
+	return movie, err
+}
+
+func (*graphqlResolver) CreateMovie(ctx context.Context, args struct{ Movie movieInput }) (*movieResolver, error) {
+	c := graphqlStateFrom(ctx).c
+
+	movie, err := graphqlMovie(ctx, args.Movie)
+	var problem graphqlError
+	if errors.As(err, &problem) {
+		return nil, err
+	}
+
+	if err == nil {
+		movie, err = database.CreateMovie(ctx, movie)
+	}
+
+	if code, detail, args, ok := referenceError(err); ok {
+		return nil, graphqlProblem(ctx, code, detail, args...)
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to create movie", "error", err)
+		return nil, graphqlProblem(ctx, dto.CodeInternalError, "Failed to create movie")
+	}
+
+	utils.Logger(c).Info("movie created", "movie_id", movie.Id)
+	return newMovieResolver(ctx, movie.Localize()), nil
+}
+
+func (*graphqlResolver) UpdateMovie(ctx context.Context, args struct {
+	Id    int32
+	Movie movieInput
+}) (*movieResolver, error) {
+	c := graphqlStateFrom(ctx).c
+	id := int(args.Id)
+
+	movie, err := graphqlMovie(ctx, args.Movie)
+	var problem graphqlError
+	if errors.As(err, &problem) {
+		return nil, err
+	}
+
+	if err == nil {
+		movie, err = database.UpdateMovie(ctx, id, movie)
+	}
+
+	if errors.Is(err, database.ErrMovieNotFound) {
+		return nil, graphqlProblem(ctx, dto.CodeMovieNotFound, "No movie with ID %d", id)
+	}
+
+	if errors.Is(err, database.ErrMovieIdExists) {
+		return nil, graphqlProblem(ctx, dto.CodeMovieExists, "Movie with updated ID already exists")
+	}
+
+	if code, detail, args, ok := referenceError(err); ok {
+		return nil, graphqlProblem(ctx, code, detail, args...)
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to update movie", "error", err)
+		return nil, graphqlProblem(ctx, dto.CodeInternalError, "Failed to update movie")
+	}
+
+	utils.Logger(c).Info("movie updated", "movie_id", id)
+	return newMovieResolver(ctx, movie.Localize()), nil
+}
+
+func (*graphqlResolver) DeleteMovie(ctx context.Context, args struct{ Id int32 }) (int32, error) {
+	c := graphqlStateFrom(ctx).c
+	id := int(args.Id)
+
+	err := database.DeleteMovie(ctx, id)
+	if errors.Is(err, database.ErrMovieNotFound) {
+		return 0, graphqlProblem(ctx, dto.CodeMovieNotFound, "No movie with ID %d", id)
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to delete movie", "error", err)
+		return 0, graphqlProblem(ctx, dto.CodeInternalError, "Failed to delete movie")
+	}
+
+	utils.Logger(c).Info("movie deleted", "movie_id", id)
+	return args.Id, nil
+}
+
+type moviePageResolver struct {
+	items []*movieResolver
+	page  int
+	limit int
+	count int
+}
+
+func (r *moviePageResolver) Items() []*movieResolver { return r.items }
+func (r *moviePageResolver) Page() int32             { return int32(r.page) }
+func (r *moviePageResolver) Limit() int32            { return int32(r.limit) }
+func (r *moviePageResolver) Count() int32            { return int32(r.count) }
+
+type movieResolver struct {
+	movie models.Movie
+}
+
+// the artists and genres of the movie are queued so every movie resolved in
+// the request has them looked up in one go
+func newMovieResolver(ctx context.Context, movie models.Movie) *movieResolver {
+	state := graphqlStateFrom(ctx)
+
+	for _, credit := range movie.Credits {
+		state.artists.Prime(credit.ArtistId)
+	}
+	state.genres.Prime(movie.GenreIds...)
+
+	return &movieResolver{movie}
+}
+
+func newMovieResolvers(ctx context.Context, movies models.Movies) []*movieResolver {
+	resolvers := make([]*movieResolver, len(movies))
+	for i, movie := range movies {
+		resolvers[i] = newMovieResolver(ctx, movie)
+	}
+
+	return resolvers
+}
+
+func (r *movieResolver) Id() int32                 { return int32(r.movie.Id) }
+func (r *movieResolver) Title() string             { return r.movie.Title }
+func (r *movieResolver) OriginalTitle() *string    { return optional(r.movie.OriginalTitle) }
+func (r *movieResolver) Tagline() *string          { return optional(r.movie.Tagline) }
+func (r *movieResolver) Description() string       { return r.movie.Description }
+func (r *movieResolver) Duration() int32           { return int32(r.movie.Duration) }
+func (r *movieResolver) ReleaseDate() *string      { return optional(r.movie.ReleaseDate) }
+func (r *movieResolver) OriginalLanguage() *string { return optional(r.movie.OriginalLanguage) }
+func (r *movieResolver) AgeCertification() *string { return optional(r.movie.AgeCertification) }
+func (r *movieResolver) Language() *string         { return optional(r.movie.Language) }
+
+func (r *movieResolver) ProductionCountries() []string {
+	if r.movie.ProductionCountries == nil {
+		return []string{}
+	}
+
+	return r.movie.ProductionCountries
+}
+
+func (r *movieResolver) credits(role *string) models.Credits {
+	if role == nil {
+		return r.movie.Credits
+	}
+
+	credits := models.Credits{}
+	for _, credit := range r.movie.Credits {
+		if string(credit.Role) == *role {
+			credits = append(credits, credit)
+		}
+	}
+
+	return credits
+}
+
+func (r *movieResolver) Credits(args struct{ Role *string }) []*creditResolver {
+	credits := r.credits(args.Role)
+
+	resolvers := make([]*creditResolver, len(credits))
+	for i, credit := range credits {
+		resolvers[i] = &creditResolver{credit}
+	}
+
+	return resolvers
+}
+
+func (r *movieResolver) Artists(ctx context.Context, args struct{ Role *string }) []*artistResolver {
+	var ids []int
+	for _, credit := range r.credits(args.Role) {
+		if !slices.Contains(ids, credit.ArtistId) {
+			ids = append(ids, credit.ArtistId)
+		}
+	}
+
+	artists := graphqlStateFrom(ctx).artists.LoadMany(ctx, ids)
+
+	resolvers := make([]*artistResolver, len(artists))
+	for i, artist := range artists {
+		resolvers[i] = &artistResolver{artist}
+	}
+
+	return resolvers
+}
+
+func (r *movieResolver) Genres(ctx context.Context) []*genreResolver {
+	genres := graphqlStateFrom(ctx).genres.LoadMany(ctx, r.movie.GenreIds)
+
+	resolvers := make([]*genreResolver, len(genres))
+	for i, genre := range genres {
+		resolvers[i] = &genreResolver{genre}
+	}
+
+	return resolvers
+}
+
+type creditResolver struct {
+	credit models.Credit
+}
+
+// movies can't credit an artist that doesn't exist, so a missing one means
+// it was deleted while the request was resolved
+func (r *creditResolver) Artist(ctx context.Context) (*artistResolver, error) {
+	artist, ok := graphqlStateFrom(ctx).artists.Load(ctx, r.credit.ArtistId)
+	if !ok {
+		return nil, graphqlProblem(ctx, dto.CodeArtistNotFound, "No artist with ID %d", r.credit.ArtistId)
+	}
+
+	return &artistResolver{artist}, nil
+}
+
+func (r *creditResolver) Role() string       { return string(r.credit.Role) }
+func (r *creditResolver) Character() *string { return optional(r.credit.Character) }
+func (r *creditResolver) Order() int32       { return int32(r.credit.Order) }
+
+type artistResolver struct {
+	artist models.Artist
+}
+
+func (r *artistResolver) Id() int32    { return int32(r.artist.Id) }
+func (r *artistResolver) Name() string { return r.artist.Name }
+
+type genreResolver struct {
+	genre models.Genre
+}
+
+func (r *genreResolver) Id() int32    { return int32(r.genre.Id) }
+func (r *genreResolver) Name() string { return r.genre.Name }
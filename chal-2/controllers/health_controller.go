@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/buildinfo"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// @Summary		Liveness probe
+// @Description	Check that the server process is up and handling requests
+// @Tags			Health
+// @Success		200	{object}	dto.BaseResponse
+// @Router			/healthz [get]
+func Healthz(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, dto.BaseResponse{
+		Message: utils.T(c, "OK"),
+		Success: true,
+	})
+}
+
+// @Summary		Readiness probe
+// @Description	Check that the storage backend is reachable and migrations have finished
+// @Tags			Health
+// @Success		200	{object}	dto.ReadinessResponse
+// @Failure		503	{object}	dto.ReadinessResponse
+// @Router			/readyz [get]
+func Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	checks := map[string]dto.HealthCheck{}
+	ready := true
+
+	if err := database.Ping(ctx); err != nil {
+		checks["storage"] = dto.HealthCheck{Status: "unavailable", Error: err.Error()}
+		ready = false
+	} else {
+		checks["storage"] = dto.HealthCheck{Status: "ok"}
+	}
+
+	if !database.Migrated() {
+		checks["migrations"] = dto.HealthCheck{Status: "pending"}
+		ready = false
+	} else {
+		checks["migrations"] = dto.HealthCheck{
+			Status: fmt.Sprintf("ok (version %d)", database.SchemaVersion()),
+		}
+	}
+
+	if !ready {
+		c.IndentedJSON(http.StatusServiceUnavailable, dto.ReadinessResponse{
+			BaseResponse: dto.BaseResponse{
+				Message: utils.T(c, "Not ready"),
+				Success: false,
+			},
+			Checks: checks,
+		})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, dto.ReadinessResponse{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Ready"),
+			Success: true,
+		},
+		Checks: checks,
+	})
+}
+
+// @Summary		Build information
+// @Description	Get the git commit, build time and Go version of the running server
+// @Tags			Health
+// @Success		200	{object}	dto.DataResponse[buildinfo.Info]
+// @Router			/version [get]
+func Version(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, dto.DataResponse[buildinfo.Info]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Version found"),
+			Success: true,
+		},
+		Data: buildinfo.Get(),
+	})
+}
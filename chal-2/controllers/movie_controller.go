@@ -1,60 +1,125 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sglkc/roketin-be-test/chal-2/database"
 	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/metrics"
 	"github.com/sglkc/roketin-be-test/chal-2/models"
 	"github.com/sglkc/roketin-be-test/chal-2/utils"
+	"github.com/sglkc/roketin-be-test/chal-2/validators"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/text/language"
 )
 
 // https://github.com/swaggo/swag/blob/master/README.md#declarative-comments-format
 
 // @Summary		Search movies
-// @Description	Search for movies by title, description, artist, or genre
+// @Description	Search for movies by title, description, artist, character, or genre, narrowed down by release year, language, country and certification
 // @Tags			Movies
 // @Param			title		query	string	false	"Movie title to search for"
 // @Param			description	query	string	false	"Movie description to search for"
 // @Param			artist		query	string	false	"Movie artist to search for"
-// @Param			genre		query	string	false	"Movie genre to search for"
-// @Param			page		query	int		false	"Page number for pagination"	default(1)
-// @Param			limit		query	int		false	"Number of movies per page"		default(10)
-// @Success		200			{array}	dto.PaginatedResponse[models.Movie]
+// @Param			character	query	string	false	"Movie character to search for"
+// @Param			role		query	string	false	"Only match artists and characters credited with this role"	Enums(actor, director, writer, composer)
+// @Param			genre			query	string	false	"Movie genre to search for"
+// @Param			year_from		query	int		false	"Only movies released in or after this year"
+// @Param			year_to			query	int		false	"Only movies released in or before this year"
+// @Param			language		query	string	false	"Only movies in this original language (ISO 639-1)"
+// @Param			country			query	string	false	"Only movies produced in this country (ISO 3166-1 alpha-2)"
+// @Param			certification	query	string	false	"Only movies with this age certification"
+// @Param			page			query	int		false	"Page number for pagination"	default(1)
+// @Param			limit			query	int		false	"Number of movies per page"		default(10)
+// @Param			lang			query	string	false	"Language to read titles, descriptions and taglines in, defaults to Accept-Language then the original language"
+// @Param			fields			query	string	false	"Comma separated fields to return, e.g. id,title,duration, defaults to every field"
+// @Param			expand			query	string	false	"Comma separated relations to embed: artists, genres"
+// @Success		200				{array}		dto.PaginatedResponse[models.Movie]
+// @Failure		400				{object}	dto.Problem
 // @Router			/movies/search [get]
 func SearchMovie(c *gin.Context) {
-	title := strings.ToLower(c.Query("title"))
-	description := strings.ToLower(c.Query("description"))
-	artist := strings.ToLower(c.Query("artist"))
-	genre := strings.ToLower(c.Query("genre"))
-
-	var filteredMovies models.Movies
-
-	for _, movie := range database.Movies {
-		movieTitle := strings.ToLower(movie.Title)
-		movieDescription := strings.ToLower(movie.Description)
-		movieArtists := strings.ToLower(strings.Join(movie.Artists, ", "))
-		movieGenres := strings.ToLower(strings.Join(movie.Genres, ", "))
-
-		if (title != "" && strings.Contains(movieTitle, title)) ||
-			(description != "" && strings.Contains(movieDescription, description)) ||
-			(artist != "" && strings.Contains(movieArtists, artist)) ||
-			(genre != "" && strings.Contains(movieGenres, genre)) {
-			filteredMovies = append(filteredMovies, movie)
+	languages, ok := contentLanguages(c)
+	if !ok {
+		return
+	}
+
+	shape, ok := movieShapeOf(c)
+	if !ok {
+		return
+	}
+
+	filter := database.MovieFilter{
+		Title:         c.Query("title"),
+		Description:   c.Query("description"),
+		Artist:        c.Query("artist"),
+		Character:     c.Query("character"),
+		Role:          models.Role(c.Query("role")),
+		Genre:         c.Query("genre"),
+		Language:      c.Query("language"),
+		Country:       c.Query("country"),
+		Certification: c.Query("certification"),
+	}
+
+	if filter.Role != "" && !slices.Contains(models.Roles, filter.Role) {
+		utils.Logger(c).Warn("invalid search role", "role", filter.Role)
+		utils.Problem(c, dto.CodeInvalidQuery, "Unknown %s value %q, expected any of %s", "role", filter.Role, "actor, director, writer, composer")
+		return
+	}
+
+	var err error
+	for param, year := range map[string]*int{"year_from": &filter.YearFrom, "year_to": &filter.YearTo} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+
+		if *year, err = strconv.Atoi(value); err != nil {
+			utils.Logger(c).Warn("invalid search year", param, value)
+			utils.Problem(c, dto.CodeInvalidQuery, "%s must be a year", param)
+			return
 		}
 	}
 
+	span := trace.SpanFromContext(c.Request.Context())
+	span.SetAttributes(
+		attribute.String("movie.search.title", filter.Title),
+		attribute.String("movie.search.description", filter.Description),
+		attribute.String("movie.search.artist", filter.Artist),
+		attribute.String("movie.search.character", filter.Character),
+		attribute.String("movie.search.role", string(filter.Role)),
+		attribute.String("movie.search.genre", filter.Genre),
+		attribute.Int("movie.search.year_from", filter.YearFrom),
+		attribute.Int("movie.search.year_to", filter.YearTo),
+		attribute.String("movie.search.language", filter.Language),
+		attribute.String("movie.search.country", filter.Country),
+		attribute.String("movie.search.certification", filter.Certification),
+	)
+
+	filteredMovies := database.SearchMovies(c.Request.Context(), filter)
+	localizeMovies(filteredMovies, languages)
+	span.SetAttributes(attribute.Int("movie.search.results", len(filteredMovies)))
+
 	data, page, limit := utils.Paginate(c, filteredMovies)
+	utils.Logger(c).Debug("movies searched", "results", len(filteredMovies))
+	metrics.SearchResults.Observe(float64(len(filteredMovies)))
+	metrics.PaginationLimit.Observe(float64(limit))
 
-	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[models.Movie]{
+	shaped, ok := shapeMovies(c, shape, data)
+	if !ok {
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[any]{
 		BaseResponse: dto.BaseResponse{
-			Message: "Movies found",
+			Message: utils.T(c, "Movies found"),
 			Success: true,
 		},
-		Data:  data,
+		Data:  shaped,
 		Page:  page,
 		Limit: limit,
 		Count: len(filteredMovies),
@@ -66,20 +131,42 @@ func SearchMovie(c *gin.Context) {
 // @Tags			Movies
 // @Param			page	query	int	false	"Page number for pagination"	default(1)
 // @Param			limit	query	int	false	"Number of movies per page"		default(10)
-// @Success		200		{array}	dto.PaginatedResponse[models.Movie]
+// @Param			lang	query	string	false	"Language to read titles, descriptions and taglines in, defaults to Accept-Language then the original language"
+// @Param			fields	query	string	false	"Comma separated fields to return, e.g. id,title,duration, defaults to every field"
+// @Param			expand	query	string	false	"Comma separated relations to embed: artists, genres"
+// @Success		200		{array}		dto.PaginatedResponse[models.Movie]
+// @Failure		400		{object}	dto.Problem
 // @Router			/movies [get]
 func GetMovies(c *gin.Context) {
-	data, page, limit := utils.Paginate(c, database.Movies)
+	languages, ok := contentLanguages(c)
+	if !ok {
+		return
+	}
+
+	shape, ok := movieShapeOf(c)
+	if !ok {
+		return
+	}
 
-	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[models.Movie]{
+	movies := database.FindMovies(c.Request.Context())
+	localizeMovies(movies, languages)
+	data, page, limit := utils.Paginate(c, movies)
+	metrics.PaginationLimit.Observe(float64(limit))
+
+	shaped, ok := shapeMovies(c, shape, data)
+	if !ok {
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[any]{
 		BaseResponse: dto.BaseResponse{
-			Message: "Movies found",
+			Message: utils.T(c, "Movies found"),
 			Success: true,
 		},
-		Data:  data,
+		Data:  shaped,
 		Page:  page,
 		Limit: limit,
-		Count: len(database.Movies),
+		Count: len(movies),
 	})
 }
 
@@ -87,76 +174,92 @@ func GetMovies(c *gin.Context) {
 // @Description	Get movie by ID
 // @Tags			Movies
 // @Param			id	path		int	true	"Movie ID"
+// @Param			lang	query	string	false	"Language to read titles, descriptions and taglines in, defaults to Accept-Language then the original language"
+// @Param			fields	query	string	false	"Comma separated fields to return, e.g. id,title,duration, defaults to every field"
+// @Param			expand	query	string	false	"Comma separated relations to embed: artists, genres"
 // @Success		200	{array}		dto.DataResponse[models.Movie]
-// @Failure		400	{object}	dto.ErrorResponse
-// @Failure		404	{object}	dto.ErrorResponse
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
 // @Router			/movies/{id} [get]
 func GetMovieById(c *gin.Context) {
 	id := c.Param("id")
 
 	idInt, err := strconv.Atoi(id)
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, dto.ErrorResponse{
-			BaseResponse: dto.BaseResponse{
-				Message: "Invalid movie ID",
-				Success: false,
-			},
-		})
+		utils.Logger(c).Warn("invalid movie id", "id", id)
+		utils.Problem(c, dto.CodeInvalidId, "Movie ID must be an integer")
 		return
 	}
 
-	movie := database.FindMovieById(idInt)
+	languages, ok := contentLanguages(c)
+	if !ok {
+		return
+	}
+
+	shape, ok := movieShapeOf(c)
+	if !ok {
+		return
+	}
+
+	movie := database.FindMovieById(c.Request.Context(), idInt)
 	if movie != nil {
-		c.IndentedJSON(http.StatusOK, dto.DataResponse[models.Movie]{
+		shaped, ok := shapeMovies(c, shape, []models.Movie{movie.Localize(languages...)})
+		if !ok {
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, dto.DataResponse[any]{
 			BaseResponse: dto.BaseResponse{
-				Message: "Movie found",
+				Message: utils.T(c, "Movie found"),
 				Success: true,
 			},
-			Data: *movie,
+			Data: shaped[0],
 		})
 		return
 	}
 
-	c.IndentedJSON(http.StatusNotFound, dto.ErrorResponse{
-		BaseResponse: dto.BaseResponse{
-			Message: "Movie not found",
-			Success: false,
-		},
-	})
+	utils.Problem(c, dto.CodeMovieNotFound, "No movie with ID %d", idInt)
 }
 
 // @Summary		Create a new movie
 // @Description	Create a new movie
 // @Tags			Movies
-// @Param			movie	body		models.Movie	true	"Movie object to create"
+// @Param			movie	body		dto.MovieRequest	true	"Movie object to create, artists and genres by ID or name"
 // @Success		201		{object}	dto.DataResponse[models.Movie]
-// @Failure		400		{object}	dto.ErrorResponse
+// @Failure		400		{object}	dto.Problem
+// @Failure		500		{object}	dto.Problem
 // @Router			/movies [post]
 func PostMovie(c *gin.Context) {
-	var newMovie models.Movie
+	var request dto.MovieRequest
 
-	if err := c.ShouldBindJSON(&newMovie); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, dto.ErrorResponse{
-			BaseResponse: dto.BaseResponse{
-				Message: "Invalid Movie body",
-				Success: false,
-			},
-		})
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger(c).Warn("invalid movie body", "error", err)
+		utils.BindProblem(c, err, "Invalid movie body")
 		return
 	}
 
-	// assume primary key is the latest ID in the list
-	database.FindMovieById(newMovie.Id)
-	database.MovieId++
-	newMovie.Id = database.MovieId
+	newMovie, err := movieFromRequest(c, request)
+	if err == nil {
+		newMovie, err = database.CreateMovie(c.Request.Context(), newMovie)
+	}
 
-	database.Movies = append(database.Movies, newMovie)
+	if referenceProblem(c, err) {
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to create movie", "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to create movie")
+		return
+	}
+
+	utils.Logger(c).Info("movie created", "movie_id", newMovie.Id)
 	c.IndentedJSON(http.StatusCreated, dto.DataResponse[models.Movie]{
 		BaseResponse: dto.BaseResponse{
-			Message: "Movie created successfully",
+			Message: utils.T(c, "Movie created successfully"),
 			Success: true,
 		},
-		Data: newMovie,
+		Data: newMovie.Localize(),
 	})
 }
 
@@ -164,67 +267,63 @@ func PostMovie(c *gin.Context) {
 // @Description	Update a movie by ID
 // @Tags			Movies
 // @Param			id		path		int				true	"Movie ID"
-// @Param			movie	body		models.Movie	true	"Updated movie object"
+// @Param			movie	body		dto.MovieRequest	true	"Updated movie object, artists and genres by ID or name"
 // @Success		200		{object}	dto.DataResponse[models.Movie]
-// @Failure		400		{object}	dto.ErrorResponse
-// @Failure		404		{object}	dto.ErrorResponse
+// @Failure		400		{object}	dto.Problem
+// @Failure		404		{object}	dto.Problem
+// @Failure		409		{object}	dto.Problem
+// @Failure		500		{object}	dto.Problem
 // @Router			/movies/{id} [put]
 func UpdateMovie(c *gin.Context) {
 	id := c.Param("id")
-	var updatedMovie models.Movie
+	var request dto.MovieRequest
 
 	idInt, err := strconv.Atoi(id)
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, dto.ErrorResponse{
-			BaseResponse: dto.BaseResponse{
-				Message: "Invalid id",
-				Success: false,
-			},
-		})
+		utils.Logger(c).Warn("invalid movie id", "id", id)
+		utils.Problem(c, dto.CodeInvalidId, "Movie ID must be an integer")
 		return
 	}
 
-	if err := c.ShouldBindJSON(&updatedMovie); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, dto.ErrorResponse{
-			BaseResponse: dto.BaseResponse{
-				Message: "Invalid Movie body",
-				Success: false,
-			},
-		})
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger(c).Warn("invalid movie body", "error", err)
+		utils.BindProblem(c, err, "Invalid movie body")
 		return
 	}
 
-	movie := database.FindMovieById(idInt)
-	if movie == nil {
-		c.IndentedJSON(http.StatusNotFound, dto.ErrorResponse{
-			BaseResponse: dto.BaseResponse{
-				Message: "Movie not found",
-				Success: false,
-			},
-		})
+	movie, err := movieFromRequest(c, request)
+	if err == nil {
+		movie, err = database.UpdateMovie(c.Request.Context(), idInt, movie)
+	}
+
+	if referenceProblem(c, err) {
+		return
+	}
+
+	if errors.Is(err, database.ErrMovieNotFound) {
+		utils.Problem(c, dto.CodeMovieNotFound, "No movie with ID %d", idInt)
 		return
 	}
 
 	// check if id is updated, if so, check if it already exists
-	if updatedMovie.Id != movie.Id {
-		if database.FindMovieById(updatedMovie.Id) != nil {
-			c.IndentedJSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Message: "Movie with updated ID already exists",
-					Success: false,
-				},
-			})
-			return
-		}
+	if errors.Is(err, database.ErrMovieIdExists) {
+		utils.Problem(c, dto.CodeMovieExists, "Movie with updated ID already exists")
+		return
 	}
 
-	*movie = updatedMovie
+	if err != nil {
+		utils.Logger(c).Error("failed to update movie", "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to update movie")
+		return
+	}
+
+	utils.Logger(c).Info("movie updated", "movie_id", idInt)
 	c.IndentedJSON(http.StatusOK, dto.DataResponse[models.Movie]{
 		BaseResponse: dto.BaseResponse{
-			Message: "Movie updated successfully",
+			Message: utils.T(c, "Movie updated successfully"),
 			Success: true,
 		},
-		Data: *movie,
+		Data: movie.Localize(),
 	})
 }
 
@@ -233,28 +332,122 @@ func UpdateMovie(c *gin.Context) {
 // @Tags			Movies
 // @Param			id	path		int	true	"Movie ID"
 // @Success		200	{object}	dto.BaseResponse
-// @Failure		404	{object}	dto.ErrorResponse
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Failure		500	{object}	dto.Problem
 // @Router			/movies/{id} [delete]
 func DeleteMovie(c *gin.Context) {
 	id := c.Param("id")
 
-	for i, movie := range database.Movies {
-		if id == strconv.Itoa(movie.Id) {
-			database.Movies = append(database.Movies[:i], database.Movies[i+1:]...)
-			c.IndentedJSON(http.StatusOK, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Message: "Movie deleted successfully",
-					Success: false,
-				},
-			})
-			return
-		}
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		utils.Logger(c).Warn("invalid movie id", "id", id)
+		utils.Problem(c, dto.CodeInvalidId, "Movie ID must be an integer")
+		return
 	}
 
-	c.IndentedJSON(http.StatusNotFound, dto.ErrorResponse{
-		BaseResponse: dto.BaseResponse{
-			Message: "Movie not found",
-			Success: false,
-		},
+	err = database.DeleteMovie(c.Request.Context(), idInt)
+	if errors.Is(err, database.ErrMovieNotFound) {
+		utils.Problem(c, dto.CodeMovieNotFound, "No movie with ID %d", idInt)
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to delete movie", "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to delete movie")
+		return
+	}
+
+	utils.Logger(c).Info("movie deleted", "movie_id", idInt)
+	c.IndentedJSON(http.StatusOK, dto.BaseResponse{
+		Message: utils.T(c, "Movie deleted successfully"),
+		Success: true,
 	})
 }
+
+// resolve the credits and genres of the request body, with artists given by
+// ID or name, into the movie to store
+func movieFromRequest(c *gin.Context, request dto.MovieRequest) (models.Movie, error) {
+	credits := request.AllCredits()
+	if len(credits) == 0 {
+		return models.Movie{}, errNoCredits
+	}
+
+	refs := make([]models.Reference, len(credits))
+	for i, credit := range credits {
+		refs[i] = credit.Artist
+	}
+
+	artistIds, err := database.ResolveArtists(c.Request.Context(), refs)
+	if err != nil {
+		return models.Movie{}, err
+	}
+
+	genreIds, err := database.ResolveGenres(c.Request.Context(), request.Genres)
+	if err != nil {
+		return models.Movie{}, err
+	}
+
+	return request.Movie(artistIds, genreIds), nil
+}
+
+var errNoCredits = errors.New("movie must credit at least one artist")
+
+// languages to read movie text in, ?lang= wins over Accept-Language and
+// neither keeps movies in their original language
+func contentLanguages(c *gin.Context) ([]string, bool) {
+	if lang := c.Query("lang"); lang != "" {
+		if !validators.IsLanguageCode(lang) {
+			utils.Problem(c, dto.CodeInvalidLanguage, "Language %q must be an ISO 639-1 code", lang)
+			return nil, false
+		}
+
+		return []string{lang}, true
+	}
+
+	return acceptLanguages(c), true
+}
+
+// unlike response messages, movies can be translated to any language so
+// every accepted language is tried in order, e.g. id-ID reads as id
+func acceptLanguages(c *gin.Context) []string {
+	tags, _, _ := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	languages := make([]string, len(tags))
+	for i, tag := range tags {
+		base, _ := tag.Base()
+		languages[i] = base.String()
+	}
+
+	return languages
+}
+
+func localizeMovies(movies models.Movies, languages []string) {
+	for i, movie := range movies {
+		movies[i] = movie.Localize(languages...)
+	}
+}
+
+// respond to errors about what the request body refers to, reported as bad
+// requests rather than as the artist or genre lookup failing
+func referenceProblem(c *gin.Context, err error) bool {
+	code, detail, args, ok := referenceError(err)
+	if ok {
+		utils.Problem(c, code, detail, args...)
+	}
+
+	return ok
+}
+
+// the problem of an error about the artists or genres a movie refers to, ok
+// is false for any other error
+func referenceError(err error) (code dto.ErrorCode, detail string, args []any, ok bool) {
+	if code, detail, args, ok := utils.ReferenceProblem(err); ok {
+		return code, detail, args, true
+	}
+
+	if errors.Is(err, errNoCredits) {
+		return dto.CodeMissingCredit, "Movie must credit at least one artist", nil, true
+	}
+
+	return "", "", nil, false
+}
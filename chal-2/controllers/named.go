@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/metrics"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// the artist and genre endpoints only differ by the record they serve, the
+// handlers of both are the methods below. Messages are built from the kind
+// and title so they match the translated ones, like "No artist with ID %d".
+type namedResource[T any, S ~[]T] struct {
+	// "artist" or "genre"
+	kind string
+	// "Artist" or "Genre"
+	title string
+
+	codeNotFound, codeExists, codeInUse dto.ErrorCode
+	errNotFound, errExists, errInUse    error
+
+	id       func(T) int
+	find     func(ctx context.Context, name string) S
+	findById func(ctx context.Context, id int) *T
+	create   func(ctx context.Context, record T) (T, error)
+	update   func(ctx context.Context, id int, record T) (T, error)
+	delete   func(ctx context.Context, id int) error
+	movies   func(ctx context.Context, id int) (models.Movies, error)
+}
+
+// the ID path parameter, answers with a problem when it isn't an integer
+func (r *namedResource[T, S]) paramId(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, r.title+" ID must be an integer")
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (r *namedResource[T, S]) getAll(c *gin.Context) {
+	records := r.find(c.Request.Context(), c.Query("name"))
+	data, page, limit := utils.Paginate(c, records)
+	metrics.PaginationLimit.Observe(float64(limit))
+
+	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[T]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, r.title+"s found"),
+			Success: true,
+		},
+		Data:  data,
+		Page:  page,
+		Limit: limit,
+		Count: len(records),
+	})
+}
+
+func (r *namedResource[T, S]) getById(c *gin.Context) {
+	id, ok := r.paramId(c)
+	if !ok {
+		return
+	}
+
+	record := r.findById(c.Request.Context(), id)
+	if record == nil {
+		utils.Problem(c, r.codeNotFound, "No "+r.kind+" with ID %d", id)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, dto.DataResponse[T]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, r.title+" found"),
+			Success: true,
+		},
+		Data: *record,
+	})
+}
+
+func (r *namedResource[T, S]) post(c *gin.Context) {
+	var newRecord T
+
+	if err := c.ShouldBindJSON(&newRecord); err != nil {
+		utils.Logger(c).Warn("invalid "+r.kind+" body", "error", err)
+		utils.BindProblem(c, err, "Invalid "+r.kind+" body")
+		return
+	}
+
+	newRecord, err := r.create(c.Request.Context(), newRecord)
+	if errors.Is(err, r.errExists) {
+		utils.Problem(c, r.codeExists, r.title+" with the same name already exists")
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to create "+r.kind, "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to create "+r.kind)
+		return
+	}
+
+	utils.Logger(c).Info(r.kind+" created", r.kind+"_id", r.id(newRecord))
+	c.IndentedJSON(http.StatusCreated, dto.DataResponse[T]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, r.title+" created successfully"),
+			Success: true,
+		},
+		Data: newRecord,
+	})
+}
+
+func (r *namedResource[T, S]) put(c *gin.Context) {
+	var updatedRecord T
+
+	id, ok := r.paramId(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&updatedRecord); err != nil {
+		utils.Logger(c).Warn("invalid "+r.kind+" body", "error", err)
+		utils.BindProblem(c, err, "Invalid "+r.kind+" body")
+		return
+	}
+
+	record, err := r.update(c.Request.Context(), id, updatedRecord)
+	if errors.Is(err, r.errNotFound) {
+		utils.Problem(c, r.codeNotFound, "No "+r.kind+" with ID %d", id)
+		return
+	}
+
+	if errors.Is(err, r.errExists) {
+		utils.Problem(c, r.codeExists, r.title+" with the same name already exists")
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to update "+r.kind, "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to update "+r.kind)
+		return
+	}
+
+	utils.Logger(c).Info(r.kind+" updated", r.kind+"_id", id)
+	c.IndentedJSON(http.StatusOK, dto.DataResponse[T]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, r.title+" updated successfully"),
+			Success: true,
+		},
+		Data: record,
+	})
+}
+
+func (r *namedResource[T, S]) remove(c *gin.Context) {
+	id, ok := r.paramId(c)
+	if !ok {
+		return
+	}
+
+	err := r.delete(c.Request.Context(), id)
+	if errors.Is(err, r.errNotFound) {
+		utils.Problem(c, r.codeNotFound, "No "+r.kind+" with ID %d", id)
+		return
+	}
+
+	if errors.Is(err, r.errInUse) {
+		utils.Problem(c, r.codeInUse, r.title+" is still referenced by a movie")
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to delete "+r.kind, "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to delete "+r.kind)
+		return
+	}
+
+	utils.Logger(c).Info(r.kind+" deleted", r.kind+"_id", id)
+	c.IndentedJSON(http.StatusOK, dto.BaseResponse{
+		Message: utils.T(c, r.title+" deleted successfully"),
+		Success: true,
+	})
+}
+
+// a localized, sorted page of the movies referring to the record, ok is false
+// when a problem was already written
+func (r *namedResource[T, S]) moviesPage(c *gin.Context) (id int, response dto.PaginatedResponse[models.Movie], ok bool) {
+	id, ok = r.paramId(c)
+	if !ok {
+		return
+	}
+
+	languages, ok := contentLanguages(c)
+	if !ok {
+		return
+	}
+
+	movies, err := r.movies(c.Request.Context(), id)
+	if err != nil {
+		utils.Problem(c, r.codeNotFound, "No "+r.kind+" with ID %d", id)
+		return id, response, false
+	}
+
+	// localized first so titles sort in the language they're shown in
+	localizeMovies(movies, languages)
+
+	if err := database.SortMovies(movies, c.Query("sort")); err != nil {
+		utils.Problem(c, dto.CodeInvalidQuery, "Unknown sort %q", c.Query("sort"))
+		return id, response, false
+	}
+
+	data, page, limit := utils.Paginate(c, movies)
+	metrics.PaginationLimit.Observe(float64(limit))
+
+	return id, dto.PaginatedResponse[models.Movie]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Movies found"),
+			Success: true,
+		},
+		Data:  data,
+		Page:  page,
+		Limit: limit,
+		Count: len(movies),
+	}, true
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/metrics"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// @Summary		Get movie revisions
+// @Description	Get the previous versions of a movie, newest first with pagination
+// @Tags			Revisions
+// @Param			id		path		int	true	"Movie ID"
+// @Param			page	query		int	false	"Page number for pagination"	default(1)
+// @Param			limit	query		int	false	"Number of revisions per page"	default(10)
+// @Success		200		{object}	dto.PaginatedResponse[models.Revision]
+// @Failure		400		{object}	dto.Problem
+// @Failure		404		{object}	dto.Problem
+// @Router			/movies/{id}/revisions [get]
+func GetMovieRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Movie ID must be an integer")
+		return
+	}
+
+	history, err := database.FindRevisions(c.Request.Context(), id)
+	if err != nil {
+		utils.Problem(c, dto.CodeMovieNotFound, "No movie with ID %d", id)
+		return
+	}
+
+	data, page, limit := utils.Paginate(c, history)
+	metrics.PaginationLimit.Observe(float64(limit))
+
+	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[models.Revision]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Revisions found"),
+			Success: true,
+		},
+		Data:  data,
+		Page:  page,
+		Limit: limit,
+		Count: len(history),
+	})
+}
+
+// @Summary		Diff a movie revision
+// @Description	Get the field changes from a revision to the current movie, or to another revision
+// @Tags			Revisions
+// @Param			id	path		int	true	"Movie ID"
+// @Param			rev	path		int	true	"Revision number"
+// @Param			to	query		int	false	"Revision number to compare with instead of the current movie"
+// @Success		200	{object}	dto.DataResponse[[]models.FieldChange]
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Router			/movies/{id}/revisions/{rev}/diff [get]
+func GetMovieRevisionDiff(c *gin.Context) {
+	id, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	to := 0
+	if value := c.Query("to"); value != "" {
+		var err error
+		if to, err = strconv.Atoi(value); err != nil {
+			utils.Problem(c, dto.CodeInvalidQuery, "%s must be an integer", "to")
+			return
+		}
+	}
+
+	changes, err := database.DiffRevision(c.Request.Context(), id, revision, to)
+	if revisionProblem(c, err, id) {
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, dto.DataResponse[[]models.FieldChange]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Revision diff found"),
+			Success: true,
+		},
+		Data: changes,
+	})
+}
+
+// @Summary		Restore a movie revision
+// @Description	Roll a movie back to a revision, the version it replaces is kept as a new revision
+// @Tags			Revisions
+// @Param			id	path		int	true	"Movie ID"
+// @Param			rev	path		int	true	"Revision number"
+// @Success		200	{object}	dto.DataResponse[models.Movie]
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Failure		500	{object}	dto.Problem
+// @Router			/movies/{id}/revisions/{rev}/restore [post]
+func RestoreMovieRevision(c *gin.Context) {
+	id, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	movie, err := database.RestoreRevision(c.Request.Context(), id, revision)
+	if revisionProblem(c, err, id) || referenceProblem(c, err) {
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to restore movie revision", "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to restore revision")
+		return
+	}
+
+	utils.Logger(c).Info("movie revision restored", "movie_id", id, "revision", revision)
+	c.IndentedJSON(http.StatusOK, dto.DataResponse[models.Movie]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Revision restored successfully"),
+			Success: true,
+		},
+		Data: movie.Localize(),
+	})
+}
+
+func revisionParams(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Movie ID must be an integer")
+		return 0, 0, false
+	}
+
+	revision, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Revision number must be an integer")
+		return 0, 0, false
+	}
+
+	return id, revision, true
+}
+
+// respond to a missing movie or revision
+func revisionProblem(c *gin.Context, err error, id int) bool {
+	switch {
+	case errors.Is(err, database.ErrMovieNotFound):
+		utils.Problem(c, dto.CodeMovieNotFound, "No movie with ID %d", id)
+	case errors.Is(err, database.ErrRevisionNotFound):
+		utils.Problem(c, dto.CodeRevisionNotFound, "Movie %d has no such revision", id)
+	default:
+		return false
+	}
+
+	return true
+}
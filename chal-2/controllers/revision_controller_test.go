@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+// create a movie with the first title and rename it to each of the others in
+// turn, every title but the last is kept as a revision
+func movieWithRevisions(t *testing.T, titles ...string) models.Movie {
+	t.Helper()
+
+	ctx := context.Background()
+	if err := database.Migrate(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	movie := database.FindMovies(ctx)[0]
+	movie.Id = 0
+	movie.Title = titles[0]
+	movie.Translations = nil
+
+	movie, err := database.CreateMovie(ctx, movie)
+	if err != nil {
+		t.Fatalf("failed to create movie: %v", err)
+	}
+	t.Cleanup(func() { database.DeleteMovie(ctx, movie.Id) })
+
+	for _, title := range titles[1:] {
+		movie.Title = title
+		if movie, err = database.UpdateMovie(ctx, movie.Id, movie); err != nil {
+			t.Fatalf("failed to update movie: %v", err)
+		}
+	}
+
+	return movie
+}
+
+func revisionRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/movies/:id/revisions", GetMovieRevisions)
+	router.GET("/movies/:id/revisions/:rev/diff", GetMovieRevisionDiff)
+	router.POST("/movies/:id/revisions/:rev/restore", RestoreMovieRevision)
+
+	return router
+}
+
+// serve the request and decode the response body into v
+func serveJson(t *testing.T, router *gin.Engine, method, path string, v any) int {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid response body %s: %v", w.Body, err)
+	}
+
+	return w.Code
+}
+
+func TestGetMovieRevisions(t *testing.T) {
+	movie := movieWithRevisions(t, "Draft", "Second Draft", "Final")
+	router := revisionRouter()
+
+	var response dto.PaginatedResponse[models.Revision]
+	if code := serveJson(t, router, http.MethodGet, fmt.Sprintf("/movies/%d/revisions", movie.Id), &response); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+
+	// newest first
+	if response.Count != 2 || len(response.Data) != 2 ||
+		response.Data[0].Revision != 2 || response.Data[0].Movie.Title != "Second Draft" ||
+		response.Data[1].Revision != 1 || response.Data[1].Movie.Title != "Draft" {
+		t.Errorf("expected revisions 2 and 1 titled Second Draft and Draft, got %+v", response.Data)
+	}
+}
+
+func TestGetMovieRevisionDiff(t *testing.T) {
+	movie := movieWithRevisions(t, "Draft", "Second Draft", "Final")
+	router := revisionRouter()
+
+	for path, want := range map[string]models.FieldChange{
+		fmt.Sprintf("/movies/%d/revisions/1/diff?to=2", movie.Id): {Field: "title", Before: "Draft", After: "Second Draft"},
+		fmt.Sprintf("/movies/%d/revisions/1/diff", movie.Id):      {Field: "title", Before: "Draft", After: "Final"},
+	} {
+		var response dto.DataResponse[[]models.FieldChange]
+		if code := serveJson(t, router, http.MethodGet, path, &response); code != http.StatusOK {
+			t.Fatalf("expected 200 from %s, got %d", path, code)
+		}
+
+		if len(response.Data) != 1 || response.Data[0] != want {
+			t.Errorf("expected %s to only change %+v, got %+v", path, want, response.Data)
+		}
+	}
+}
+
+func TestRestoreMovieRevision(t *testing.T) {
+	movie := movieWithRevisions(t, "Draft", "Second Draft", "Final")
+	router := revisionRouter()
+	audited := len(database.FindAuditEntries(context.Background(), database.AuditFilter{MovieId: movie.Id}))
+
+	var response dto.DataResponse[models.Movie]
+	if code := serveJson(t, router, http.MethodPost, fmt.Sprintf("/movies/%d/revisions/1/restore", movie.Id), &response); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+
+	if response.Data.Id != movie.Id || response.Data.Title != "Draft" {
+		t.Errorf("expected movie %d to be titled Draft again, got %d %q", movie.Id, response.Data.Id, response.Data.Title)
+	}
+
+	// the version the restore replaced is kept so it can be undone
+	history, err := database.FindRevisions(context.Background(), movie.Id)
+	if err != nil || len(history) != 3 || history[0].Revision != 3 || history[0].Movie.Title != "Final" {
+		t.Errorf("expected the restore to keep Final as revision 3, got %+v %v", history, err)
+	}
+
+	entries := database.FindAuditEntries(context.Background(), database.AuditFilter{MovieId: movie.Id})
+	if len(entries) != audited+1 || entries[0].Action != models.AuditUpdate ||
+		len(entries[0].Changes) != 1 || entries[0].Changes[0].After != "Draft" {
+		t.Errorf("expected the restore to be audited as an update to Draft, got %+v", entries)
+	}
+}
+
+func TestRevisionsNotFound(t *testing.T) {
+	movie := movieWithRevisions(t, "Draft", "Final")
+	router := revisionRouter()
+
+	for _, test := range []struct {
+		method, path string
+		code         dto.ErrorCode
+	}{
+		{http.MethodGet, "/movies/999999/revisions", dto.CodeMovieNotFound},
+		{http.MethodGet, "/movies/999999/revisions/1/diff", dto.CodeMovieNotFound},
+		{http.MethodPost, "/movies/999999/revisions/1/restore", dto.CodeMovieNotFound},
+		{http.MethodGet, fmt.Sprintf("/movies/%d/revisions/5/diff", movie.Id), dto.CodeRevisionNotFound},
+		{http.MethodGet, fmt.Sprintf("/movies/%d/revisions/1/diff?to=5", movie.Id), dto.CodeRevisionNotFound},
+		{http.MethodPost, fmt.Sprintf("/movies/%d/revisions/5/restore", movie.Id), dto.CodeRevisionNotFound},
+	} {
+		var problem dto.Problem
+		if code := serveJson(t, router, test.method, test.path, &problem); code != http.StatusNotFound || problem.Code != test.code {
+			t.Errorf("expected %s %s to be a 404 %s, got %d %s", test.method, test.path, test.code, code, problem.Code)
+		}
+	}
+}
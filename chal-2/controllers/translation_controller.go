@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+	"github.com/sglkc/roketin-be-test/chal-2/validators"
+)
+
+// @Summary		Get movie translations
+// @Description	Get every translation of a movie's title, description and tagline by language
+// @Tags			Translations
+// @Param			id	path		int	true	"Movie ID"
+// @Success		200	{object}	dto.DataResponse[models.Translations]
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Router			/movies/{id}/translations [get]
+func GetMovieTranslations(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Movie ID must be an integer")
+		return
+	}
+
+	translations, err := database.FindTranslations(c.Request.Context(), id)
+	if err != nil {
+		utils.Problem(c, dto.CodeMovieNotFound, "No movie with ID %d", id)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, dto.DataResponse[models.Translations]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Translations found"),
+			Success: true,
+		},
+		Data: translations,
+	})
+}
+
+// @Summary		Add or edit a movie translation
+// @Description	Add a translation of a movie to a language, or replace the existing one
+// @Tags			Translations
+// @Param			id			path		int					true	"Movie ID"
+// @Param			lang		path		string				true	"ISO 639-1 language code"
+// @Param			translation	body		models.Translation	true	"Translated title, description and tagline"
+// @Success		200			{object}	dto.DataResponse[models.Translation]
+// @Failure		400			{object}	dto.Problem
+// @Failure		404			{object}	dto.Problem
+// @Failure		500			{object}	dto.Problem
+// @Router			/movies/{id}/translations/{lang} [put]
+func PutMovieTranslation(c *gin.Context) {
+	var translation models.Translation
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Movie ID must be an integer")
+		return
+	}
+
+	lang := c.Param("lang")
+	if !validators.IsLanguageCode(lang) {
+		utils.Problem(c, dto.CodeInvalidLanguage, "Language %q must be an ISO 639-1 code", lang)
+		return
+	}
+
+	if err := c.ShouldBindJSON(&translation); err != nil {
+		utils.Logger(c).Warn("invalid translation body", "error", err)
+		utils.BindProblem(c, err, "Invalid translation body")
+		return
+	}
+
+	err = database.SetTranslation(c.Request.Context(), id, lang, translation)
+	if errors.Is(err, database.ErrMovieNotFound) {
+		utils.Problem(c, dto.CodeMovieNotFound, "No movie with ID %d", id)
+		return
+	}
+
+	if errors.Is(err, database.ErrOriginalLanguage) {
+		utils.Problem(c, dto.CodeInvalidLanguage, "The movie is originally in %s, edit the movie instead", lang)
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to save translation", "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to save translation")
+		return
+	}
+
+	utils.Logger(c).Info("translation saved", "movie_id", id, "language", lang)
+	c.IndentedJSON(http.StatusOK, dto.DataResponse[models.Translation]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Translation saved successfully"),
+			Success: true,
+		},
+		Data: translation,
+	})
+}
+
+// @Summary		Delete a movie translation
+// @Description	Delete the translation of a movie to a language
+// @Tags			Translations
+// @Param			id		path		int		true	"Movie ID"
+// @Param			lang	path		string	true	"ISO 639-1 language code"
+// @Success		200		{object}	dto.BaseResponse
+// @Failure		400		{object}	dto.Problem
+// @Failure		404		{object}	dto.Problem
+// @Failure		500		{object}	dto.Problem
+// @Router			/movies/{id}/translations/{lang} [delete]
+func DeleteMovieTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Movie ID must be an integer")
+		return
+	}
+
+	lang := c.Param("lang")
+
+	err = database.DeleteTranslation(c.Request.Context(), id, lang)
+	if errors.Is(err, database.ErrMovieNotFound) {
+		utils.Problem(c, dto.CodeMovieNotFound, "No movie with ID %d", id)
+		return
+	}
+
+	if errors.Is(err, database.ErrTranslationNotFound) {
+		utils.Problem(c, dto.CodeTranslationNotFound, "Movie %d has no %s translation", id, lang)
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to delete translation", "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to delete translation")
+		return
+	}
+
+	utils.Logger(c).Info("translation deleted", "movie_id", id, "language", lang)
+	c.IndentedJSON(http.StatusOK, dto.BaseResponse{
+		Message: utils.T(c, "Translation deleted successfully"),
+		Success: true,
+	})
+}
@@ -0,0 +1,349 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/metrics"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+	"github.com/sglkc/roketin-be-test/chal-2/webhooks"
+)
+
+// secrets are only shown once, when the webhook is created
+func hideSecret(webhook models.Webhook) models.Webhook {
+	webhook.Secret = ""
+	return webhook
+}
+
+// respond with a problem when the URL of the webhook isn't allowed
+func webhookUrlProblem(c *gin.Context, rawUrl string) bool {
+	err := webhooks.CheckUrl(c.Request.Context(), rawUrl)
+	if err == nil {
+		return false
+	}
+
+	utils.Logger(c).Warn("webhook url not allowed", "url", rawUrl, "error", err)
+	utils.Problem(c, dto.CodeWebhookUrlNotAllowed, "Webhook URL %q must point to a public address", rawUrl)
+	return true
+}
+
+func webhookFromRequest(request dto.WebhookRequest) models.Webhook {
+	webhook := models.Webhook{
+		Url:    request.Url,
+		Events: request.Events,
+		Active: request.Active == nil || *request.Active,
+		Secret: request.Secret,
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = []models.EventType{}
+	}
+
+	return webhook
+}
+
+// @Summary		Get all webhooks
+// @Description	Get a list of all webhooks with pagination, secrets are left out
+// @Tags			Webhooks
+// @Param			page	query	int	false	"Page number for pagination"	default(1)
+// @Param			limit	query	int	false	"Number of webhooks per page"	default(10)
+// @Success		200		{object}	dto.PaginatedResponse[models.Webhook]
+// @Failure		401		{object}	dto.Problem
+// @Router			/webhooks [get]
+func GetWebhooks(c *gin.Context) {
+	hooks := database.FindWebhooks(c.Request.Context())
+	for i, webhook := range hooks {
+		hooks[i] = hideSecret(webhook)
+	}
+
+	data, page, limit := utils.Paginate(c, hooks)
+	metrics.PaginationLimit.Observe(float64(limit))
+
+	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[models.Webhook]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Webhooks found"),
+			Success: true,
+		},
+		Data:  data,
+		Page:  page,
+		Limit: limit,
+		Count: len(hooks),
+	})
+}
+
+// @Summary		Get webhook
+// @Description	Get webhook by ID, the secret is left out
+// @Tags			Webhooks
+// @Param			id	path		int	true	"Webhook ID"
+// @Success		200	{object}	dto.DataResponse[models.Webhook]
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Failure		401	{object}	dto.Problem
+// @Router			/webhooks/{id} [get]
+func GetWebhookById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Webhook ID must be an integer")
+		return
+	}
+
+	webhook := database.FindWebhookById(c.Request.Context(), id)
+	if webhook == nil {
+		utils.Problem(c, dto.CodeWebhookNotFound, "No webhook with ID %d", id)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, dto.DataResponse[models.Webhook]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Webhook found"),
+			Success: true,
+		},
+		Data: hideSecret(*webhook),
+	})
+}
+
+// @Summary		Create a new webhook
+// @Description	Subscribe a URL to movie events, an empty events list subscribes to all of them. Deliveries are signed with the secret, which is generated when not given and only returned here
+// @Tags			Webhooks
+// @Param			webhook	body		dto.WebhookRequest	true	"Webhook object to create"
+// @Success		201		{object}	dto.DataResponse[models.Webhook]
+// @Failure		400		{object}	dto.Problem
+// @Failure		500		{object}	dto.Problem
+// @Failure		401		{object}	dto.Problem
+// @Router			/webhooks [post]
+func PostWebhook(c *gin.Context) {
+	var request dto.WebhookRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger(c).Warn("invalid webhook body", "error", err)
+		utils.BindProblem(c, err, "Invalid webhook body")
+		return
+	}
+
+	if webhookUrlProblem(c, request.Url) {
+		return
+	}
+
+	newWebhook := webhookFromRequest(request)
+	if newWebhook.Secret == "" {
+		newWebhook.Secret = webhooks.NewSecret()
+	}
+
+	newWebhook, err := database.CreateWebhook(c.Request.Context(), newWebhook)
+	if err != nil {
+		utils.Logger(c).Error("failed to create webhook", "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to create webhook")
+		return
+	}
+
+	utils.Logger(c).Info("webhook created", "webhook_id", newWebhook.Id)
+	c.IndentedJSON(http.StatusCreated, dto.DataResponse[models.Webhook]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Webhook created successfully"),
+			Success: true,
+		},
+		Data: newWebhook,
+	})
+}
+
+// @Summary		Update a webhook
+// @Description	Replace a webhook's URL, events and active flag by ID, the secret is only rotated when given
+// @Tags			Webhooks
+// @Param			id		path		int					true	"Webhook ID"
+// @Param			webhook	body		dto.WebhookRequest	true	"Updated webhook object"
+// @Success		200		{object}	dto.DataResponse[models.Webhook]
+// @Failure		400		{object}	dto.Problem
+// @Failure		404		{object}	dto.Problem
+// @Failure		500		{object}	dto.Problem
+// @Failure		401		{object}	dto.Problem
+// @Router			/webhooks/{id} [put]
+func UpdateWebhook(c *gin.Context) {
+	var request dto.WebhookRequest
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Webhook ID must be an integer")
+		return
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger(c).Warn("invalid webhook body", "error", err)
+		utils.BindProblem(c, err, "Invalid webhook body")
+		return
+	}
+
+	if webhookUrlProblem(c, request.Url) {
+		return
+	}
+
+	webhook, err := database.UpdateWebhook(c.Request.Context(), id, webhookFromRequest(request))
+	if errors.Is(err, database.ErrWebhookNotFound) {
+		utils.Problem(c, dto.CodeWebhookNotFound, "No webhook with ID %d", id)
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to update webhook", "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to update webhook")
+		return
+	}
+
+	utils.Logger(c).Info("webhook updated", "webhook_id", id)
+	c.IndentedJSON(http.StatusOK, dto.DataResponse[models.Webhook]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Webhook updated successfully"),
+			Success: true,
+		},
+		Data: hideSecret(webhook),
+	})
+}
+
+// @Summary		Delete a webhook
+// @Description	Delete a webhook by ID along with its dead letters
+// @Tags			Webhooks
+// @Param			id	path		int	true	"Webhook ID"
+// @Success		200	{object}	dto.BaseResponse
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Failure		500	{object}	dto.Problem
+// @Failure		401	{object}	dto.Problem
+// @Router			/webhooks/{id} [delete]
+func DeleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Webhook ID must be an integer")
+		return
+	}
+
+	err = database.DeleteWebhook(c.Request.Context(), id)
+	if errors.Is(err, database.ErrWebhookNotFound) {
+		utils.Problem(c, dto.CodeWebhookNotFound, "No webhook with ID %d", id)
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to delete webhook", "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to delete webhook")
+		return
+	}
+
+	utils.Logger(c).Info("webhook deleted", "webhook_id", id)
+	c.IndentedJSON(http.StatusOK, dto.BaseResponse{
+		Message: utils.T(c, "Webhook deleted successfully"),
+		Success: true,
+	})
+}
+
+// @Summary		Get webhook deliveries
+// @Description	Get the delivery attempts of a webhook, newest first with pagination. Only the latest attempts across all webhooks are kept
+// @Tags			Webhooks
+// @Param			id		path		int	true	"Webhook ID"
+// @Param			page	query		int	false	"Page number for pagination"	default(1)
+// @Param			limit	query		int	false	"Number of deliveries per page"	default(10)
+// @Success		200		{object}	dto.PaginatedResponse[models.WebhookDelivery]
+// @Failure		400		{object}	dto.Problem
+// @Failure		404		{object}	dto.Problem
+// @Failure		401		{object}	dto.Problem
+// @Router			/webhooks/{id}/deliveries [get]
+func GetWebhookDeliveries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Webhook ID must be an integer")
+		return
+	}
+
+	deliveries, err := database.FindDeliveries(c.Request.Context(), id)
+	if err != nil {
+		utils.Problem(c, dto.CodeWebhookNotFound, "No webhook with ID %d", id)
+		return
+	}
+
+	data, page, limit := utils.Paginate(c, deliveries)
+	metrics.PaginationLimit.Observe(float64(limit))
+
+	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[models.WebhookDelivery]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Deliveries found"),
+			Success: true,
+		},
+		Data:  data,
+		Page:  page,
+		Limit: limit,
+		Count: len(deliveries),
+	})
+}
+
+// @Summary		Get dead letters
+// @Description	Get the events that failed every delivery attempt, newest first with pagination
+// @Tags			Webhooks
+// @Param			page	query	int	false	"Page number for pagination"		default(1)
+// @Param			limit	query	int	false	"Number of dead letters per page"	default(10)
+// @Success		200		{object}	dto.PaginatedResponse[models.DeadLetter]
+// @Failure		401		{object}	dto.Problem
+// @Router			/webhooks/dead-letters [get]
+func GetDeadLetters(c *gin.Context) {
+	letters := database.FindDeadLetters(c.Request.Context())
+	data, page, limit := utils.Paginate(c, letters)
+	metrics.PaginationLimit.Observe(float64(limit))
+
+	c.IndentedJSON(http.StatusOK, dto.PaginatedResponse[models.DeadLetter]{
+		BaseResponse: dto.BaseResponse{
+			Message: utils.T(c, "Dead letters found"),
+			Success: true,
+		},
+		Data:  data,
+		Page:  page,
+		Limit: limit,
+		Count: len(letters),
+	})
+}
+
+// @Summary		Redeliver a dead letter
+// @Description	Remove a dead letter and deliver its event again with a fresh set of attempts, it's dead-lettered again if they all fail
+// @Tags			Webhooks
+// @Param			id	path		int	true	"Dead letter ID"
+// @Success		202	{object}	dto.BaseResponse
+// @Failure		400	{object}	dto.Problem
+// @Failure		404	{object}	dto.Problem
+// @Failure		500	{object}	dto.Problem
+// @Failure		401	{object}	dto.Problem
+// @Router			/webhooks/dead-letters/{id}/redeliver [post]
+func RedeliverDeadLetter(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.Problem(c, dto.CodeInvalidId, "Dead letter ID must be an integer")
+		return
+	}
+
+	letter, err := database.TakeDeadLetter(c.Request.Context(), id)
+	if errors.Is(err, database.ErrDeadLetterNotFound) {
+		utils.Problem(c, dto.CodeDeadLetterNotFound, "No dead letter with ID %d", id)
+		return
+	}
+
+	if err != nil {
+		utils.Logger(c).Error("failed to redeliver dead letter", "error", err)
+		utils.Problem(c, dto.CodeInternalError, "Failed to redeliver dead letter")
+		return
+	}
+
+	// dead letters are deleted with their webhook, so it's still there
+	webhook := database.FindWebhookById(c.Request.Context(), letter.WebhookId)
+	if webhook == nil {
+		utils.Problem(c, dto.CodeWebhookNotFound, "No webhook with ID %d", letter.WebhookId)
+		return
+	}
+
+	webhooks.Redeliver(c.Request.Context(), *webhook, letter)
+
+	utils.Logger(c).Info("dead letter redelivered", "dead_letter_id", id, "webhook_id", webhook.Id)
+	c.IndentedJSON(http.StatusAccepted, dto.BaseResponse{
+		Message: utils.T(c, "Dead letter queued for redelivery"),
+		Success: true,
+	})
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+)
+
+var (
+	ErrArtistNotFound = errors.New("artist not found")
+	ErrArtistExists   = errors.New("artist with the same name already exists")
+	ErrArtistInUse    = errors.New("artist is still referenced by a movie")
+)
+
+var artistId int = 0
+
+var artists models.Artists
+
+var artistStore = &namedStore[models.Artist, models.Artists]{
+	kind:     "artist",
+	lastId:   &artistId,
+	records:  &artists,
+	notFound: ErrArtistNotFound,
+	exists:   ErrArtistExists,
+	inUse:    ErrArtistInUse,
+	id:       func(artist models.Artist) int { return artist.Id },
+	name:     func(artist models.Artist) string { return artist.Name },
+	set: func(artist *models.Artist, id int, name string) {
+		artist.Id, artist.Name = id, name
+	},
+	// credited in any role
+	usedBy: func(movie models.Movie, id int) bool {
+		return slices.ContainsFunc(movie.Credits, func(credit models.Credit) bool {
+			return credit.ArtistId == id
+		})
+	},
+}
+
+func artistName(id int) string {
+	if i := artistStore.index(id); i != -1 {
+		return artists[i].Name
+	}
+
+	return ""
+}
+
+// list artists, optionally only those whose name contains the given string
+func FindArtists(ctx context.Context, name string) models.Artists {
+	_, span := tracing.Tracer.Start(ctx, "database.FindArtists")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return artistStore.find(span, name)
+}
+
+func FindArtistById(ctx context.Context, id int) *models.Artist {
+	_, span := tracing.Tracer.Start(ctx, "database.FindArtistById")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return artistStore.findById(span, id)
+}
+
+// look up several artists at once, IDs without an artist are left out
+func FindArtistsByIds(ctx context.Context, ids []int) map[int]models.Artist {
+	_, span := tracing.Tracer.Start(ctx, "database.FindArtistsByIds")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return artistStore.findByIds(span, ids)
+}
+
+func CreateArtist(ctx context.Context, artist models.Artist) (models.Artist, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.CreateArtist")
+	defer span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return artistStore.create(span, artist)
+}
+
+// renaming an artist applies to every movie since movies refer to it by ID
+func UpdateArtist(ctx context.Context, id int, artist models.Artist) (models.Artist, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.UpdateArtist")
+	defer span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return artistStore.update(span, id, artist)
+}
+
+func DeleteArtist(ctx context.Context, id int) error {
+	_, span := tracing.Tracer.Start(ctx, "database.DeleteArtist")
+	defer span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return artistStore.delete(span, id)
+}
+
+// turn references by ID or name into artist IDs
+func ResolveArtists(ctx context.Context, refs []models.Reference) ([]int, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.ResolveArtists")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return artistStore.resolve(refs)
+}
+
+// the ID of the artist referred to by ID or name, false if there's none
+func LookupArtist(ctx context.Context, ref models.Reference) (int, bool) {
+	_, span := tracing.Tracer.Start(ctx, "database.LookupArtist")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return artistStore.lookup(ref)
+}
+
+// the filmography of an artist, every movie crediting the artist in any role
+func FindMoviesByArtist(ctx context.Context, id int) (models.Movies, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.FindMoviesByArtist")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return artistStore.movies(span, id)
+}
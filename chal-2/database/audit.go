@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// AnonymousActor is recorded for mutations made without an authenticated actor
+const AnonymousActor = "anonymous"
+
+var (
+	auditId  int
+	auditLog []models.AuditEntry
+)
+
+// swapped in tests that need a fixed clock
+var now = time.Now
+
+type actorKey struct{}
+
+// attach who is making the request, mutations made with the context are
+// audited under this actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func actorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+
+	return AnonymousActor
+}
+
+// append an entry for the mutation, must be called inside mutate so the entry
+// is kept or rolled back together with the mutation. Nil before or after
+// means the movie was created or deleted.
+func audit(ctx context.Context, action models.AuditAction, movieId int, before, after *models.Movie) {
+	auditId++
+	auditLog = append(auditLog, models.AuditEntry{
+		Id:        auditId,
+		Actor:     actorFrom(ctx),
+		Timestamp: now().UTC(),
+		Action:    action,
+		MovieId:   movieId,
+		Changes:   diffMovies(before, after),
+	})
+}
+
+// field level diff by the movies' JSON fields, so nested fields such as
+// credits compare as a whole and field names match the API
+func diffMovies(before, after *models.Movie) []models.FieldChange {
+	beforeFields := movieFields(before)
+	afterFields := movieFields(after)
+
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []models.FieldChange{}
+	for _, name := range names {
+		if !reflect.DeepEqual(beforeFields[name], afterFields[name]) {
+			changes = append(changes, models.FieldChange{
+				Field:  name,
+				Before: beforeFields[name],
+				After:  afterFields[name],
+			})
+		}
+	}
+
+	return changes
+}
+
+func movieFields(movie *models.Movie) map[string]any {
+	fields := map[string]any{}
+	if movie == nil {
+		return fields
+	}
+
+	data, _ := json.Marshal(movie)
+	json.Unmarshal(data, &fields)
+
+	return fields
+}
+
+// zero values match any entry, the time range is inclusive
+type AuditFilter struct {
+	MovieId int
+	Actor   string
+	From    time.Time
+	To      time.Time
+}
+
+// audit entries matching the filter, newest first
+func FindAuditEntries(ctx context.Context, filter AuditFilter) []models.AuditEntry {
+	_, span := tracing.Tracer.Start(ctx, "database.FindAuditEntries")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var entries []models.AuditEntry
+	for _, entry := range slices.Backward(auditLog) {
+		if (filter.MovieId == 0 || entry.MovieId == filter.MovieId) &&
+			(filter.Actor == "" || entry.Actor == filter.Actor) &&
+			(filter.From.IsZero() || !entry.Timestamp.Before(filter.From)) &&
+			(filter.To.IsZero() || !entry.Timestamp.After(filter.To)) {
+			entries = append(entries, entry)
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("db.scanned_count", len(auditLog)),
+		attribute.Int("db.result_count", len(entries)),
+	)
+
+	return entries
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+func TestAudit(t *testing.T) {
+	ctx := context.Background()
+	if err := Migrate(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	fixed := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixed }
+	defer func() { now = time.Now }()
+
+	movie := FindMovies(ctx)[0]
+	changed := cloneMovie(movie)
+	changed.Title = movie.Title + " (Director's Cut)"
+
+	if _, err := UpdateMovie(WithActor(ctx, "alice"), movie.Id, changed); err != nil {
+		t.Fatalf("failed to update movie: %v", err)
+	}
+	defer UpdateMovie(ctx, movie.Id, movie)
+
+	entries := FindAuditEntries(ctx, AuditFilter{MovieId: movie.Id, Actor: "alice"})
+	if len(entries) == 0 {
+		t.Fatal("expected the update to be audited under alice")
+	}
+
+	entry := entries[0]
+	if entry.Action != models.AuditUpdate || !entry.Timestamp.Equal(fixed) {
+		t.Errorf("expected an update at %s, got %s at %s", fixed, entry.Action, entry.Timestamp)
+	}
+
+	if len(entry.Changes) != 1 || entry.Changes[0].Field != "title" ||
+		entry.Changes[0].Before != movie.Title || entry.Changes[0].After != changed.Title {
+		t.Errorf("expected only the title to change, got %+v", entry.Changes)
+	}
+
+	// an entry written by a mutation that fails goes with it
+	mu.Lock()
+	audited, id := len(auditLog), auditId
+	err := mutate(func() error {
+		audit(ctx, models.AuditDelete, movie.Id, &movie, nil)
+		return errors.New("failed")
+	})
+	rolledBack := len(auditLog) == audited && auditId == id
+	mu.Unlock()
+
+	if err == nil || !rolledBack {
+		t.Errorf("expected the entry of the failed mutation to be rolled back, got %v", err)
+	}
+
+	if entries := FindAuditEntries(ctx, AuditFilter{Actor: AnonymousActor, From: fixed}); len(entries) != 0 {
+		t.Errorf("expected no entries from the failed mutation, got %+v", entries)
+	}
+}
@@ -1,47 +1,375 @@
 package database
 
-import "github.com/sglkc/roketin-be-test/chal-2/models"
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 
-var MovieId int = 0
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
 
-var Movies = []models.Movie{
-	{
-		Id:          1,
-		Title:       "Final Destination: Bloodlines",
-		Description: "Plagued by a recurring violent nightmare, a college student returns home to find the one person who can break the cycle and save her family from the horrific fate that inevitably awaits them.",
-		Duration:    90,
-		Artists:     []string{"Kaitlyn Santa Juana", "Teo Briones", "Rya Kihlstedt"},
-		Genres:      []string{"Horror", "Splatter Horror"},
-	},
-	{
-		Id:          2,
-		Title:       "Mission: Impossible - The Final Reckoning",
-		Description: "Our lives are the sum of our choices. Tom Cruise is Ethan Hunt in Mission: Impossible - The Final Reckoning.",
-		Duration:    169,
-		Artists:     []string{"Tom Cruise", "Haylett Atwell", "Ving Rhames"},
-		Genres:      []string{"Action", "Adeventure", "Thriller"},
-	},
+var (
+	ErrMovieNotFound = errors.New("movie not found")
+	ErrMovieIdExists = errors.New("movie with updated ID already exists")
+)
+
+// a reference of a movie to an artist or genre that doesn't exist, matches
+// ErrArtistNotFound or ErrGenreNotFound with errors.Is
+type ReferenceError struct {
+	// "artist" or "genre"
+	Kind string
+	// the ID or name that wasn't found
+	Ref string
+}
+
+func (err *ReferenceError) Error() string {
+	return err.Kind + " not found: " + err.Ref
+}
+
+func (err *ReferenceError) Unwrap() error {
+	if err.Kind == "genre" {
+		return ErrGenreNotFound
+	}
+
+	return ErrArtistNotFound
 }
 
-// find movie by id also get latest primary key while at it
-func FindMovieById(id int) *models.Movie {
-	for _, movie := range Movies {
-		if movie.Id > MovieId {
-			MovieId = movie.Id
+// movies can only refer to existing artists and genres, callers must hold
+// the lock
+func checkReferences(movie models.Movie) error {
+	for _, credit := range movie.Credits {
+		if artistStore.index(credit.ArtistId) == -1 {
+			return &ReferenceError{Kind: "artist", Ref: strconv.Itoa(credit.ArtistId)}
 		}
+	}
 
-		if movie.Id == id {
-			return &movie
+	for _, id := range movie.GenreIds {
+		if genreStore.index(id) == -1 {
+			return &ReferenceError{Kind: "genre", Ref: strconv.Itoa(id)}
 		}
 	}
 
 	return nil
 }
 
-func main() {
-	for _, movie := range Movies {
-		if movie.Id > MovieId {
-			MovieId = movie.Id + 1
+// handlers run concurrently, every access to the movies goes through this lock
+var mu sync.RWMutex
+
+var movieId int = 0
+
+var movies models.Movies
+
+// search is case insensitive and matches a movie if any of the given text
+// fields is a substring of the movie's field. Role narrows the artist and
+// character to credits with that role, e.g. the movies directed by an artist.
+// The remaining fields filter the result, all of them must match.
+type MovieFilter struct {
+	Title       string
+	Description string
+	Artist      string
+	Character   string
+	Role        models.Role
+	Genre       string
+
+	YearFrom      int
+	YearTo        int
+	Language      string
+	Country       string
+	Certification string
+}
+
+func (filter MovieFilter) hasText() bool {
+	return filter.Title != "" || filter.Description != "" || filter.Artist != "" ||
+		filter.Character != "" || filter.Genre != ""
+}
+
+func (filter MovieFilter) hasMetadata() bool {
+	return filter.YearFrom != 0 || filter.YearTo != 0 || filter.Language != "" ||
+		filter.Country != "" || filter.Certification != ""
+}
+
+func (filter MovieFilter) matchesMetadata(movie models.Movie) bool {
+	year := movie.ReleaseYear()
+
+	return (filter.YearFrom == 0 || (year != 0 && year >= filter.YearFrom)) &&
+		(filter.YearTo == 0 || (year != 0 && year <= filter.YearTo)) &&
+		(filter.Language == "" || strings.EqualFold(movie.OriginalLanguage, filter.Language)) &&
+		(filter.Country == "" || slices.ContainsFunc(movie.ProductionCountries, func(country string) bool {
+			return strings.EqualFold(country, filter.Country)
+		})) &&
+		(filter.Certification == "" || strings.EqualFold(movie.AgeCertification, filter.Certification))
+}
+
+// copy the slices too so callers can't modify the stored movie
+func cloneMovie(movie models.Movie) models.Movie {
+	movie.Credits = slices.Clone(movie.Credits)
+	movie.ProductionCountries = slices.Clone(movie.ProductionCountries)
+	movie.GenreIds = slices.Clone(movie.GenreIds)
+	movie.Translations = maps.Clone(movie.Translations)
+
+	return movie
+}
+
+func cloneMovies(movies models.Movies) models.Movies {
+	result := make(models.Movies, len(movies))
+	for i, movie := range movies {
+		result[i] = cloneMovie(movie)
+	}
+
+	return result
+}
+
+func indexOfMovie(id int) int {
+	return slices.IndexFunc(movies, func(movie models.Movie) bool {
+		return movie.Id == id
+	})
+}
+
+// check that the store can still serve requests, a stuck lock would otherwise
+// hang every handler
+func Ping(ctx context.Context) error {
+	result := make(chan error, 1)
+
+	go func() {
+		mu.RLock()
+		defer mu.RUnlock()
+
+		if closed {
+			result <- ErrClosed
+			return
+		}
+
+		result <- pingStorage(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func CountMovies(ctx context.Context) int {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return len(movies)
+}
+
+func FindMovies(ctx context.Context) models.Movies {
+	_, span := tracing.Tracer.Start(ctx, "database.FindMovies")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := cloneMovies(movies)
+	span.SetAttributes(attribute.Int("db.result_count", len(result)))
+
+	return result
+}
+
+func FindMovieById(ctx context.Context, id int) *models.Movie {
+	_, span := tracing.Tracer.Start(ctx, "database.FindMovieById")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("movie.id", id))
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	i := indexOfMovie(id)
+	if i == -1 {
+		return nil
+	}
+
+	movie := cloneMovie(movies[i])
+	return &movie
+}
+
+func SearchMovies(ctx context.Context, filter MovieFilter) models.Movies {
+	_, span := tracing.Tracer.Start(ctx, "database.SearchMovies")
+	defer span.End()
+
+	title := strings.ToLower(filter.Title)
+	description := strings.ToLower(filter.Description)
+	artist := strings.ToLower(filter.Artist)
+	character := strings.ToLower(filter.Character)
+	genre := strings.ToLower(filter.Genre)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var filteredMovies models.Movies
+
+	for _, movie := range movies {
+		if !filter.matchesMetadata(movie) {
+			continue
+		}
+
+		// metadata filters alone list every movie matching them
+		if !filter.hasText() {
+			if filter.hasMetadata() {
+				filteredMovies = append(filteredMovies, cloneMovie(movie))
+			}
+			continue
+		}
+
+		// titles and descriptions match in any language
+		movieTitle := strings.ToLower(movie.Title)
+		movieDescription := strings.ToLower(movie.Description)
+		for _, translation := range movie.Translations {
+			movieTitle += "\n" + strings.ToLower(translation.Title)
+			movieDescription += "\n" + strings.ToLower(translation.Description)
+		}
+		movieGenres := strings.ToLower(strings.Join(genreNames(movie.GenreIds), ", "))
+		var movieArtists, movieCharacters []string
+
+		for _, credit := range movie.Credits {
+			if filter.Role == "" || credit.Role == filter.Role {
+				movieArtists = append(movieArtists, artistName(credit.ArtistId))
+				movieCharacters = append(movieCharacters, credit.Character)
+			}
+		}
+
+		if (title != "" && strings.Contains(movieTitle, title)) ||
+			(description != "" && strings.Contains(movieDescription, description)) ||
+			(artist != "" && strings.Contains(strings.ToLower(strings.Join(movieArtists, ", ")), artist)) ||
+			(character != "" && strings.Contains(strings.ToLower(strings.Join(movieCharacters, ", ")), character)) ||
+			(genre != "" && strings.Contains(movieGenres, genre)) {
+			filteredMovies = append(filteredMovies, cloneMovie(movie))
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("db.scanned_count", len(movies)),
+		attribute.Int("db.result_count", len(filteredMovies)),
+	)
+
+	return filteredMovies
+}
+
+// assign the next primary key to the movie and store it
+func CreateMovie(ctx context.Context, movie models.Movie) (models.Movie, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.CreateMovie")
+	defer span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	movie, err := createMovie(ctx, movie)
+
+	span.SetAttributes(attribute.Int("movie.id", movie.Id))
+
+	return movie, err
+}
+
+// CreateMovie with the lock held
+func createMovie(ctx context.Context, movie models.Movie) (models.Movie, error) {
+	if err := checkReferences(movie); err != nil {
+		return movie, err
+	}
+
+	movie.Credits.Sort()
+
+	err := mutate(func() error {
+		movieId++
+		movie.Id = movieId
+		movies = append(movies, cloneMovie(movie))
+		audit(ctx, models.AuditCreate, movie.Id, nil, &movie)
+		addEvent(ctx, models.EventMovieCreated, movie.Id, &movie)
+		return nil
+	})
+
+	return movie, err
+}
+
+// replace the movie with the given id, the updated movie may carry a new id
+// as long as it isn't taken, a zero id keeps the current one
+func UpdateMovie(ctx context.Context, id int, movie models.Movie) (models.Movie, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.UpdateMovie")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("movie.id", id))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return updateMovie(ctx, id, movie)
+}
+
+// UpdateMovie with the lock held, the replaced version is kept as a revision
+func updateMovie(ctx context.Context, id int, movie models.Movie) (models.Movie, error) {
+	i := indexOfMovie(id)
+	if i == -1 {
+		return movie, ErrMovieNotFound
+	}
+
+	if movie.Id == 0 {
+		movie.Id = id
+	}
+
+	// translations are edited on their own and survive updates
+	movie.Translations = movies[i].Translations
+
+	if movie.Id != id && indexOfMovie(movie.Id) != -1 {
+		return movie, ErrMovieIdExists
+	}
+
+	if err := checkReferences(movie); err != nil {
+		return movie, err
+	}
+
+	movie.Credits.Sort()
+
+	err := mutate(func() error {
+		if movie.Id > movieId {
+			movieId = movie.Id
 		}
+
+		before := movies[i]
+		movies[i] = cloneMovie(movie)
+		addRevision(ctx, before, movie.Id)
+		audit(ctx, models.AuditUpdate, id, &before, &movie)
+		addEvent(ctx, models.EventMovieUpdated, movie.Id, &movies[i])
+		return nil
+	})
+
+	return movie, err
+}
+
+func DeleteMovie(ctx context.Context, id int) error {
+	_, span := tracing.Tracer.Start(ctx, "database.DeleteMovie")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("movie.id", id))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return deleteMovie(ctx, id)
+}
+
+// DeleteMovie with the lock held
+func deleteMovie(ctx context.Context, id int) error {
+	i := indexOfMovie(id)
+	if i == -1 {
+		return ErrMovieNotFound
 	}
+
+	return mutate(func() error {
+		before := movies[i]
+		movies = slices.Delete(movies, i, i+1)
+		delete(revisions, id)
+		audit(ctx, models.AuditDelete, id, &before, nil)
+		addEvent(ctx, models.EventMovieDeleted, id, &before)
+		return nil
+	})
 }
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+)
+
+var (
+	ErrGenreNotFound = errors.New("genre not found")
+	ErrGenreExists   = errors.New("genre with the same name already exists")
+	ErrGenreInUse    = errors.New("genre is still referenced by a movie")
+)
+
+var genreId int = 0
+
+var genres models.Genres
+
+var genreStore = &namedStore[models.Genre, models.Genres]{
+	kind:     "genre",
+	lastId:   &genreId,
+	records:  &genres,
+	notFound: ErrGenreNotFound,
+	exists:   ErrGenreExists,
+	inUse:    ErrGenreInUse,
+	id:       func(genre models.Genre) int { return genre.Id },
+	name:     func(genre models.Genre) string { return genre.Name },
+	set: func(genre *models.Genre, id int, name string) {
+		genre.Id, genre.Name = id, name
+	},
+	usedBy: func(movie models.Movie, id int) bool {
+		return slices.Contains(movie.GenreIds, id)
+	},
+}
+
+func genreNames(ids []int) []string {
+	names := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if i := genreStore.index(id); i != -1 {
+			names = append(names, genres[i].Name)
+		}
+	}
+
+	return names
+}
+
+// list genres, optionally only those whose name contains the given string
+func FindGenres(ctx context.Context, name string) models.Genres {
+	_, span := tracing.Tracer.Start(ctx, "database.FindGenres")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return genreStore.find(span, name)
+}
+
+func FindGenreById(ctx context.Context, id int) *models.Genre {
+	_, span := tracing.Tracer.Start(ctx, "database.FindGenreById")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return genreStore.findById(span, id)
+}
+
+// look up several genres at once, IDs without a genre are left out
+func FindGenresByIds(ctx context.Context, ids []int) map[int]models.Genre {
+	_, span := tracing.Tracer.Start(ctx, "database.FindGenresByIds")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return genreStore.findByIds(span, ids)
+}
+
+func CreateGenre(ctx context.Context, genre models.Genre) (models.Genre, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.CreateGenre")
+	defer span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return genreStore.create(span, genre)
+}
+
+// renaming a genre applies to every movie since movies refer to it by ID
+func UpdateGenre(ctx context.Context, id int, genre models.Genre) (models.Genre, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.UpdateGenre")
+	defer span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return genreStore.update(span, id, genre)
+}
+
+func DeleteGenre(ctx context.Context, id int) error {
+	_, span := tracing.Tracer.Start(ctx, "database.DeleteGenre")
+	defer span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return genreStore.delete(span, id)
+}
+
+// turn references by ID or name into genre IDs
+func ResolveGenres(ctx context.Context, refs []models.Reference) ([]int, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.ResolveGenres")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return genreStore.resolve(refs)
+}
+
+// the ID of the genre referred to by ID or name, false if there's none
+func LookupGenre(ctx context.Context, ref models.Reference) (int, bool) {
+	_, span := tracing.Tracer.Start(ctx, "database.LookupGenre")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return genreStore.lookup(ref)
+}
+
+func FindMoviesByGenre(ctx context.Context, id int) (models.Movies, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.FindMoviesByGenre")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return genreStore.movies(span, id)
+}
@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+type migration struct {
+	version int
+	name    string
+	up      func() error
+}
+
+// migrations are applied in order and only once, append new ones at the end
+var migrations = []migration{
+	{1, "seed movies", seedMovies},
+	{2, "normalize artists and genres", normalizeArtistsAndGenres},
+	{3, "fix seed artist and genre typos", fixSeedTypos},
+	{4, "credit artists as actors", creditArtistsAsActors},
+	{5, "add seed movie metadata", addSeedMetadata},
+}
+
+var schemaVersion int
+
+var migrated atomic.Bool
+
+// apply pending migrations, the store reports ready once this returns
+func Migrate(ctx context.Context) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, m := range migrations {
+		if m.version <= schemaVersion {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := mutate(func() error {
+			if err := m.up(); err != nil {
+				return err
+			}
+
+			schemaVersion = m.version
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		slog.Info("migration applied", "version", m.version, "name", m.name)
+	}
+
+	migrated.Store(true)
+
+	return nil
+}
+
+func Migrated() bool {
+	return migrated.Load()
+}
+
+func SchemaVersion() int {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return schemaVersion
+}
+
+func seedMovies() error {
+	movies = append(movies, models.Movies{
+		{
+			Id:          1,
+			Title:       "Final Destination: Bloodlines",
+			Description: "Plagued by a recurring violent nightmare, a college student returns home to find the one person who can break the cycle and save her family from the horrific fate that inevitably awaits them.",
+			Duration:    90,
+		},
+		{
+			Id:          2,
+			Title:       "Mission: Impossible - The Final Reckoning",
+			Description: "Our lives are the sum of our choices. Tom Cruise is Ethan Hunt in Mission: Impossible - The Final Reckoning.",
+			Duration:    169,
+		},
+	}...)
+
+	// seeded as names like the original movies, migration 2 normalizes them
+	legacy[1] = legacyNames{
+		Artists: []string{"Kaitlyn Santa Juana", "Teo Briones", "Rya Kihlstedt"},
+		Genres:  []string{"Horror", "Splatter Horror"},
+	}
+	legacy[2] = legacyNames{
+		Artists: []string{"Tom Cruise", "Haylett Atwell", "Ving Rhames"},
+		Genres:  []string{"Action", "Adeventure", "Thriller"},
+	}
+
+	for _, movie := range movies {
+		if movie.Id > movieId {
+			movieId = movie.Id
+		}
+	}
+
+	return nil
+}
+
+// replace the artist and genre names of every movie with IDs of artist and
+// genre records, names differing only by case or spaces share one record
+func normalizeArtistsAndGenres() error {
+	for i, movie := range movies {
+		names := legacy[movie.Id]
+		var artistIds []int
+
+		for _, name := range names.Artists {
+			j := artistStore.indexOfName(name)
+			if j == -1 {
+				artistId++
+				artists = append(artists, models.Artist{Id: artistId, Name: strings.TrimSpace(name)})
+				j = len(artists) - 1
+			}
+
+			if !slices.Contains(artistIds, artists[j].Id) {
+				artistIds = append(artistIds, artists[j].Id)
+			}
+		}
+
+		for _, name := range names.Genres {
+			j := genreStore.indexOfName(name)
+			if j == -1 {
+				genreId++
+				genres = append(genres, models.Genre{Id: genreId, Name: strings.TrimSpace(name)})
+				j = len(genres) - 1
+			}
+
+			if !slices.Contains(movies[i].GenreIds, genres[j].Id) {
+				movies[i].GenreIds = append(movies[i].GenreIds, genres[j].Id)
+			}
+		}
+
+		legacy[movie.Id] = legacyNames{ArtistIds: artistIds}
+	}
+
+	return nil
+}
+
+// the original seed data had typos, renaming the record fixes every movie
+func fixSeedTypos() error {
+	if i := artistStore.indexOfName("Haylett Atwell"); i != -1 && artistStore.indexOfName("Hayley Atwell") == -1 {
+		artists[i].Name = "Hayley Atwell"
+	}
+
+	if i := genreStore.indexOfName("Adeventure"); i != -1 && genreStore.indexOfName("Adventure") == -1 {
+		genres[i].Name = "Adventure"
+	}
+
+	return nil
+}
+
+// movies only listed artist IDs before credits had roles, keep the listed
+// order as the billing order
+func creditArtistsAsActors() error {
+	for i, movie := range movies {
+		for j, id := range legacy[movie.Id].ArtistIds {
+			movies[i].Credits = append(movies[i].Credits, models.Credit{
+				ArtistId: id,
+				Role:     models.RoleActor,
+				Order:    j + 1,
+			})
+		}
+	}
+
+	legacy = map[int]legacyNames{}
+
+	return nil
+}
+
+// fill in the metadata of the seed movies, matched by title so movies that
+// were renamed or created since are left alone
+func addSeedMetadata() error {
+	metadata := map[string]models.Movie{
+		"Final Destination: Bloodlines": {
+			ReleaseDate:         "2025-05-16",
+			OriginalLanguage:    "en",
+			ProductionCountries: []string{"US"},
+			AgeCertification:    "R",
+		},
+		"Mission: Impossible - The Final Reckoning": {
+			ReleaseDate:         "2025-05-23",
+			OriginalLanguage:    "en",
+			ProductionCountries: []string{"US"},
+			AgeCertification:    "PG-13",
+		},
+	}
+
+	for i, movie := range movies {
+		seed, ok := metadata[movie.Title]
+		if !ok || movie.ReleaseDate != "" {
+			continue
+		}
+
+		movies[i].ReleaseDate = seed.ReleaseDate
+		movies[i].OriginalLanguage = seed.OriginalLanguage
+		movies[i].ProductionCountries = seed.ProductionCountries
+		movies[i].AgeCertification = seed.AgeCertification
+	}
+
+	return nil
+}
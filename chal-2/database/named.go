@@ -0,0 +1,203 @@
+package database
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// artists and genres are both records with an ID and a unique name that
+// movies refer to, they're stored and looked up the same way. The exported
+// functions start the spans and hold the lock, the helpers below only work on
+// the records.
+type namedStore[T any, S ~[]T] struct {
+	// "artist" or "genre", used for span attributes and reference errors
+	kind    string
+	lastId  *int
+	records *S
+
+	notFound, exists, inUse error
+
+	id   func(T) int
+	name func(T) string
+	set  func(record *T, id int, name string)
+	// whether the movie refers to the record with the given ID
+	usedBy func(movie models.Movie, id int) bool
+}
+
+func (s *namedStore[T, S]) index(id int) int {
+	return slices.IndexFunc(*s.records, func(record T) bool {
+		return s.id(record) == id
+	})
+}
+
+// names are unique regardless of case and surrounding spaces
+func (s *namedStore[T, S]) indexOfName(name string) int {
+	name = strings.TrimSpace(name)
+
+	return slices.IndexFunc(*s.records, func(record T) bool {
+		return strings.EqualFold(s.name(record), name)
+	})
+}
+
+// the records whose name contains the given string
+func (s *namedStore[T, S]) find(span trace.Span, name string) S {
+	name = strings.ToLower(name)
+
+	result := S{}
+	for _, record := range *s.records {
+		if strings.Contains(strings.ToLower(s.name(record)), name) {
+			result = append(result, record)
+		}
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", len(result)))
+
+	return result
+}
+
+func (s *namedStore[T, S]) findById(span trace.Span, id int) *T {
+	span.SetAttributes(attribute.Int(s.kind+".id", id))
+
+	i := s.index(id)
+	if i == -1 {
+		return nil
+	}
+
+	record := (*s.records)[i]
+	return &record
+}
+
+func (s *namedStore[T, S]) findByIds(span trace.Span, ids []int) map[int]T {
+	span.SetAttributes(attribute.IntSlice(s.kind+".ids", ids))
+
+	result := make(map[int]T, len(ids))
+	for _, record := range *s.records {
+		if id := s.id(record); slices.Contains(ids, id) {
+			result[id] = record
+		}
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", len(result)))
+
+	return result
+}
+
+func (s *namedStore[T, S]) create(span trace.Span, record T) (T, error) {
+	name := strings.TrimSpace(s.name(record))
+	s.set(&record, s.id(record), name)
+
+	if s.indexOfName(name) != -1 {
+		return record, s.exists
+	}
+
+	err := mutate(func() error {
+		*s.lastId++
+		s.set(&record, *s.lastId, name)
+		*s.records = append(*s.records, record)
+		return nil
+	})
+
+	span.SetAttributes(attribute.Int(s.kind+".id", s.id(record)))
+
+	return record, err
+}
+
+func (s *namedStore[T, S]) update(span trace.Span, id int, record T) (T, error) {
+	span.SetAttributes(attribute.Int(s.kind+".id", id))
+
+	i := s.index(id)
+	if i == -1 {
+		return record, s.notFound
+	}
+
+	s.set(&record, id, strings.TrimSpace(s.name(record)))
+
+	if j := s.indexOfName(s.name(record)); j != -1 && j != i {
+		return record, s.exists
+	}
+
+	err := mutate(func() error {
+		(*s.records)[i] = record
+		return nil
+	})
+
+	return record, err
+}
+
+// records still referred to by a movie can't be deleted
+func (s *namedStore[T, S]) delete(span trace.Span, id int) error {
+	span.SetAttributes(attribute.Int(s.kind+".id", id))
+
+	i := s.index(id)
+	if i == -1 {
+		return s.notFound
+	}
+
+	for _, movie := range movies {
+		if s.usedBy(movie, id) {
+			return s.inUse
+		}
+	}
+
+	return mutate(func() error {
+		*s.records = slices.Delete(*s.records, i, i+1)
+		return nil
+	})
+}
+
+// turn references by ID or name into record IDs
+func (s *namedStore[T, S]) resolve(refs []models.Reference) ([]int, error) {
+	ids := make([]int, 0, len(refs))
+
+	for _, ref := range refs {
+		id, ok := s.lookup(ref)
+		if !ok {
+			return nil, &ReferenceError{Kind: s.kind, Ref: ref.String()}
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// the ID of the record referred to by ID or name, if there's one
+func (s *namedStore[T, S]) lookup(ref models.Reference) (int, bool) {
+	i := -1
+
+	if ref.Id != 0 {
+		i = s.index(ref.Id)
+	} else if ref.Name != "" {
+		i = s.indexOfName(ref.Name)
+	}
+
+	if i == -1 {
+		return 0, false
+	}
+
+	return s.id((*s.records)[i]), true
+}
+
+// every movie referring to the record
+func (s *namedStore[T, S]) movies(span trace.Span, id int) (models.Movies, error) {
+	span.SetAttributes(attribute.Int(s.kind+".id", id))
+
+	if s.index(id) == -1 {
+		return nil, s.notFound
+	}
+
+	result := models.Movies{}
+	for _, movie := range movies {
+		if s.usedBy(movie, id) {
+			result = append(result, cloneMovie(movie))
+		}
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", len(result)))
+
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"slices"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// an event waiting to be published, written in the same mutation as the
+// change it describes so one is never stored without the other. The trace
+// context of the change goes with it so its deliveries join the same trace.
+type OutboxEntry struct {
+	Id    int               `json:"id"`
+	Event models.Event      `json:"event"`
+	Trace map[string]string `json:"trace,omitempty"`
+}
+
+// the context to publish the entry with, carrying the trace of the change
+func (entry OutboxEntry) Context(ctx context.Context) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(entry.Trace))
+}
+
+var (
+	outboxId int
+	outbox   []OutboxEntry
+)
+
+// signalled after a mutation adds events so the relay doesn't have to wait
+// for its next poll
+var outboxSignal = make(chan struct{}, 1)
+
+// add an event about the movie to the outbox, called inside mutate. Movies
+// are stored in events the way the API shows them in their original language
+func addEvent(ctx context.Context, eventType models.EventType, movieId int, movie *models.Movie) {
+	if movie != nil {
+		localized := cloneMovie(*movie).Localize()
+		movie = &localized
+	}
+
+	trace := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, trace)
+
+	outboxId++
+	outbox = append(outbox, OutboxEntry{
+		Id:    outboxId,
+		Event: models.NewMovieEvent(eventType, movieId, movie),
+		Trace: trace,
+	})
+
+	select {
+	case outboxSignal <- struct{}{}:
+	default:
+	}
+}
+
+// receives after events are added to the outbox, a rolled back mutation may
+// still signal so the outbox can be empty
+func OutboxSignal() <-chan struct{} {
+	return outboxSignal
+}
+
+// the oldest events not published yet, in the order they happened
+func PendingEvents(ctx context.Context, limit int) []OutboxEntry {
+	_, span := tracing.Tracer.Start(ctx, "database.PendingEvents")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := slices.Clone(outbox[:min(limit, len(outbox))])
+
+	span.SetAttributes(attribute.Int("db.result_count", len(result)))
+
+	return result
+}
+
+// remove published events from the outbox in one mutation
+func MarkPublished(ctx context.Context, ids ...int) error {
+	_, span := tracing.Tracer.Start(ctx, "database.MarkPublished")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("outbox.count", len(ids)))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !slices.ContainsFunc(outbox, func(entry OutboxEntry) bool {
+		return slices.Contains(ids, entry.Id)
+	}) {
+		return nil
+	}
+
+	return mutate(func() error {
+		outbox = slices.DeleteFunc(slices.Clone(outbox), func(entry OutboxEntry) bool {
+			return slices.Contains(ids, entry.Id)
+		})
+		return nil
+	})
+}
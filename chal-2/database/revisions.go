@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// previous versions by movie ID, oldest first and numbered from 1
+var revisions = map[int][]models.Revision{}
+
+// keep the replaced version of a movie, must be called inside mutate. The
+// history follows the movie when the update gave it a new ID.
+func addRevision(ctx context.Context, replaced models.Movie, newId int) {
+	history := revisions[replaced.Id]
+	delete(revisions, replaced.Id)
+
+	// translations aren't versioned, they're edited on their own
+	replaced = cloneMovie(replaced)
+	replaced.Translations = nil
+
+	revisions[newId] = append(slices.Clip(history), models.Revision{
+		Revision:  len(history) + 1,
+		Actor:     actorFrom(ctx),
+		Timestamp: now().UTC(),
+		Movie:     replaced,
+	})
+}
+
+// revisions of the movie, newest first
+func FindRevisions(ctx context.Context, movieId int) ([]models.Revision, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.FindRevisions")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("movie.id", movieId))
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if indexOfMovie(movieId) == -1 {
+		return nil, ErrMovieNotFound
+	}
+
+	history := slices.Clone(revisions[movieId])
+	slices.Reverse(history)
+	span.SetAttributes(attribute.Int("db.result_count", len(history)))
+
+	return history, nil
+}
+
+func findRevision(movieId, revision int) (models.Revision, error) {
+	if indexOfMovie(movieId) == -1 {
+		return models.Revision{}, ErrMovieNotFound
+	}
+
+	history := revisions[movieId]
+	if revision < 1 || revision > len(history) {
+		return models.Revision{}, ErrRevisionNotFound
+	}
+
+	return history[revision-1], nil
+}
+
+// field changes from the revision to another revision of the movie, or to
+// the current version when to is 0
+func DiffRevision(ctx context.Context, movieId, revision, to int) ([]models.FieldChange, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.DiffRevision")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("movie.id", movieId),
+		attribute.Int("revision", revision),
+		attribute.Int("revision.to", to),
+	)
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	from, err := findRevision(movieId, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	target := cloneMovie(movies[indexOfMovie(movieId)])
+	target.Translations = nil
+
+	if to != 0 {
+		toRevision, err := findRevision(movieId, to)
+		if err != nil {
+			return nil, err
+		}
+
+		target = toRevision.Movie
+	}
+
+	return diffMovies(&from.Movie, &target), nil
+}
+
+// put the movie back to a revision, the version it replaces becomes a new
+// revision so a restore can be undone too
+func RestoreRevision(ctx context.Context, movieId, revision int) (models.Movie, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.RestoreRevision")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("movie.id", movieId),
+		attribute.Int("revision", revision),
+	)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	restored, err := findRevision(movieId, revision)
+	if err != nil {
+		return models.Movie{}, err
+	}
+
+	// the movie keeps its current ID even if the revision had another one
+	movie := cloneMovie(restored.Movie)
+	movie.Id = movieId
+
+	return updateMovie(ctx, movieId, movie)
+}
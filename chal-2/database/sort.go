@@ -0,0 +1,66 @@
+package database
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+// sort keys accepted by SortMovies, prefix with - for descending order
+var movieSorts = map[string]func(a, b models.Movie) int{
+	"id": func(a, b models.Movie) int {
+		return cmp.Compare(a.Id, b.Id)
+	},
+	// movies without a release date sort as if unreleased, after every date
+	"release_date": func(a, b models.Movie) int {
+		return cmp.Or(
+			cmp.Compare(undated(a), undated(b)),
+			cmp.Compare(a.ReleaseDate, b.ReleaseDate),
+			cmp.Compare(a.Id, b.Id),
+		)
+	},
+	"title": func(a, b models.Movie) int {
+		return cmp.Or(
+			cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)),
+			cmp.Compare(a.Id, b.Id),
+		)
+	},
+}
+
+func undated(movie models.Movie) int {
+	if movie.ReleaseDate == "" {
+		return 1
+	}
+
+	return 0
+}
+
+// sort movies in place by a key such as "title" or "-title", an empty key
+// keeps the stored order
+func SortMovies(movies models.Movies, by string) error {
+	if by == "" {
+		return nil
+	}
+
+	key, descending := strings.CutPrefix(by, "-")
+
+	compare, ok := movieSorts[key]
+	if !ok {
+		return ErrInvalidSort
+	}
+
+	slices.SortStableFunc(movies, func(a, b models.Movie) int {
+		if descending {
+			return compare(b, a)
+		}
+
+		return compare(a, b)
+	})
+
+	return nil
+}
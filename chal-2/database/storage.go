@@ -0,0 +1,235 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/shutdown"
+)
+
+// the file backend keeps everything in memory and writes a JSON snapshot of
+// the store after every mutation, the memory backend leaves filePath empty
+var filePath string
+
+var closed bool
+
+var ErrClosed = errors.New("database is closed")
+
+// the whole store, used both for the file backend and to roll back a
+// mutation that couldn't be persisted. The audit and delivery logs are only
+// filled in for the file, the audit log only grows so a rollback truncates
+// it instead of copying it.
+type snapshot struct {
+	SchemaVersion int                       `json:"schema_version"`
+	MovieId       int                       `json:"movie_id"`
+	Movies        []storedMovie             `json:"movies"`
+	ArtistId      int                       `json:"artist_id"`
+	Artists       models.Artists            `json:"artists"`
+	GenreId       int                       `json:"genre_id"`
+	Genres        models.Genres             `json:"genres"`
+	AuditId       int                       `json:"audit_id"`
+	AuditLog      []models.AuditEntry       `json:"audit_log"`
+	Revisions     map[int][]models.Revision `json:"revisions"`
+	WebhookId     int                       `json:"webhook_id"`
+	Webhooks      models.Webhooks           `json:"webhooks"`
+	DeliveryId    int                       `json:"delivery_id"`
+	Deliveries    []models.WebhookDelivery  `json:"deliveries"`
+	DeadLetterId  int                       `json:"dead_letter_id"`
+	DeadLetters   []models.DeadLetter       `json:"dead_letters"`
+	OutboxId      int                       `json:"outbox_id"`
+	Outbox        []OutboxEntry             `json:"outbox"`
+}
+
+type storedMovie struct {
+	models.Movie
+	legacyNames
+}
+
+// movies stored before migration 2 listed artists and genres by name, and
+// before migration 4 listed artists by ID without credits
+type legacyNames struct {
+	Artists   []string `json:"artists,omitempty"`
+	Genres    []string `json:"genres,omitempty"`
+	ArtistIds []int    `json:"artist_ids,omitempty"`
+}
+
+// legacy fields by movie ID, empty once every migration has run
+var legacy = map[int]legacyNames{}
+
+func takeSnapshot() snapshot {
+	s := snapshot{
+		SchemaVersion: schemaVersion,
+		MovieId:       movieId,
+		Movies:        make([]storedMovie, len(movies)),
+		ArtistId:      artistId,
+		Artists:       slices.Clone(artists),
+		GenreId:       genreId,
+		Genres:        slices.Clone(genres),
+		AuditId:       auditId,
+		Revisions:     maps.Clone(revisions),
+		WebhookId:     webhookId,
+		Webhooks:      slices.Clone(webhooks),
+		DeadLetterId:  deadLetterId,
+		DeadLetters:   slices.Clone(deadLetters),
+		OutboxId:      outboxId,
+		Outbox:        slices.Clone(outbox),
+	}
+
+	for i, movie := range movies {
+		s.Movies[i] = storedMovie{cloneMovie(movie), legacy[movie.Id]}
+	}
+
+	return s
+}
+
+func restoreSnapshot(s snapshot) {
+	schemaVersion = s.SchemaVersion
+	movieId = s.MovieId
+	movies = make(models.Movies, len(s.Movies))
+	artistId = s.ArtistId
+	artists = s.Artists
+	genreId = s.GenreId
+	genres = s.Genres
+	auditId = s.AuditId
+	revisions = s.Revisions
+	webhookId = s.WebhookId
+	webhooks = s.Webhooks
+	deadLetterId = s.DeadLetterId
+	deadLetters = s.DeadLetters
+	outboxId = s.OutboxId
+	outbox = s.Outbox
+	if revisions == nil {
+		revisions = map[int][]models.Revision{}
+	}
+	legacy = map[int]legacyNames{}
+
+	for i, movie := range s.Movies {
+		movies[i] = movie.Movie
+
+		if len(movie.Artists) > 0 || len(movie.Genres) > 0 || len(movie.ArtistIds) > 0 {
+			legacy[movie.Id] = movie.legacyNames
+		}
+	}
+}
+
+// select the storage backend, must be called before Migrate. Close is
+// registered as a shutdown hook so the last state is flushed on exit.
+func Open(backend, dsn string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	shutdown.Register("database", Close)
+
+	switch backend {
+	case "memory":
+		filePath = ""
+		return nil
+	case "file":
+		filePath = dsn
+	default:
+		return fmt.Errorf("unknown storage backend %q", backend)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", filePath, err)
+	}
+
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("parse %s: %w", filePath, err)
+	}
+
+	restoreSnapshot(s)
+	auditLog = s.AuditLog
+	restoreDeliveryLog(s.DeliveryId, s.Deliveries)
+
+	return nil
+}
+
+// flush the snapshot one last time, every mutation after this is rejected
+func Close(ctx context.Context) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	err := persist()
+	closed = true
+
+	return err
+}
+
+// write the snapshot to a temporary file first so a crash mid-write can't
+// corrupt the existing one, callers must hold the lock
+func persist() error {
+	if filePath == "" {
+		return nil
+	}
+
+	s := takeSnapshot()
+	s.AuditLog = auditLog
+	s.DeliveryId, s.Deliveries = deliveryLog()
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	tmp := filePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", tmp, err)
+	}
+
+	return os.Rename(tmp, filePath)
+}
+
+// run a mutation and persist it, the in-memory state is restored when either
+// fails so memory and disk never disagree
+func mutate(fn func() error) error {
+	if closed {
+		return ErrClosed
+	}
+
+	// the transaction rolls back as a whole, its own snapshot covers this
+	if inTransaction {
+		return fn()
+	}
+
+	previous := takeSnapshot()
+	audited := len(auditLog)
+
+	rollback := func() {
+		restoreSnapshot(previous)
+		auditLog = auditLog[:audited]
+	}
+
+	if err := fn(); err != nil {
+		rollback()
+		return err
+	}
+
+	if err := persist(); err != nil {
+		rollback()
+		return err
+	}
+
+	return nil
+}
+
+func pingStorage(ctx context.Context) error {
+	if filePath == "" {
+		return nil
+	}
+
+	_, err := os.Stat(filepath.Dir(filePath))
+	return err
+}
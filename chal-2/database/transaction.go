@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+)
+
+// set while a transaction runs, mutations inside it leave rolling back and
+// persisting to the transaction
+var inTransaction bool
+
+// the mutations available inside a transaction, each works like the
+// function of the same name
+type Tx struct {
+	ctx context.Context
+}
+
+// run fn as one mutation, when it returns an error every change it made is
+// rolled back and nothing is persisted
+func Transaction(ctx context.Context, fn func(tx Tx) error) error {
+	ctx, span := tracing.Tracer.Start(ctx, "database.Transaction")
+	defer span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return mutate(func() error {
+		inTransaction = true
+		defer func() { inTransaction = false }()
+
+		return fn(Tx{ctx})
+	})
+}
+
+func (tx Tx) CreateMovie(movie models.Movie) (models.Movie, error) {
+	return createMovie(tx.ctx, movie)
+}
+
+func (tx Tx) UpdateMovie(id int, movie models.Movie) (models.Movie, error) {
+	return updateMovie(tx.ctx, id, movie)
+}
+
+func (tx Tx) DeleteMovie(id int) error {
+	return deleteMovie(tx.ctx, id)
+}
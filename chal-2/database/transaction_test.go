@@ -0,0 +1,147 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+func TestTransactionRollsBack(t *testing.T) {
+	ctx := context.Background()
+	if err := Migrate(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	mu.RLock()
+	before := takeSnapshot()
+	audited := len(auditLog)
+	mu.RUnlock()
+
+	err := Transaction(ctx, func(tx Tx) error {
+		created, err := tx.CreateMovie(models.Movie{
+			Title:       "Top Gun: Maverick",
+			Description: "After more than thirty years of service, Maverick is still pushing the envelope.",
+			Duration:    131,
+			Credits:     models.Credits{{ArtistId: before.Artists[0].Id, Role: models.RoleActor}},
+			GenreIds:    []int{before.Genres[0].Id},
+		})
+		if err != nil {
+			t.Fatalf("failed to create movie: %v", err)
+		}
+
+		if err := tx.DeleteMovie(before.Movies[0].Id); err != nil {
+			t.Fatalf("failed to delete movie: %v", err)
+		}
+
+		if _, err := tx.UpdateMovie(created.Id, models.Movie{Title: "Top Gun"}); err != nil {
+			t.Fatalf("failed to update movie: %v", err)
+		}
+
+		return tx.DeleteMovie(-1)
+	})
+	if !errors.Is(err, ErrMovieNotFound) {
+		t.Fatalf("expected the failing delete to fail the transaction, got %v", err)
+	}
+
+	mu.RLock()
+	after := takeSnapshot()
+	auditedAfter := len(auditLog)
+	mu.RUnlock()
+
+	if !reflect.DeepEqual(before.Movies, after.Movies) || before.MovieId != after.MovieId {
+		t.Errorf("expected the movies to be rolled back, got movie ID %d from %d", after.MovieId, before.MovieId)
+	}
+
+	if auditedAfter != audited || before.AuditId != after.AuditId {
+		t.Errorf("expected no audit entries, got %d from %d", auditedAfter, audited)
+	}
+
+	if !reflect.DeepEqual(before.Outbox, after.Outbox) || before.OutboxId != after.OutboxId {
+		t.Errorf("expected no events in the outbox, got %d from %d", len(after.Outbox), len(before.Outbox))
+	}
+}
+
+func TestTransactionPersistsOnce(t *testing.T) {
+	ctx := context.Background()
+	if err := Migrate(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "movies.json")
+	if err := Open("file", path); err != nil {
+		t.Fatalf("failed to open the file backend: %v", err)
+	}
+	defer Open("memory", "")
+
+	mu.Lock()
+	err := persist()
+	mu.Unlock()
+	if err != nil {
+		t.Fatalf("failed to persist: %v", err)
+	}
+
+	stored := func() []byte {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read the store: %v", err)
+		}
+
+		return data
+	}
+	before := stored()
+
+	var created models.Movie
+	apply := func(fail bool) error {
+		return Transaction(ctx, func(tx Tx) error {
+			movie := cloneMovie(movies[0])
+			movie.Id = 0
+			movie.Title = "Halfway"
+
+			var err error
+			if created, err = tx.CreateMovie(movie); err != nil {
+				return err
+			}
+
+			// the mutation inside the transaction isn't written on its own
+			if data := stored(); !bytes.Equal(data, before) {
+				t.Error("expected nothing to be persisted before the transaction ends")
+			}
+
+			if fail {
+				return errors.New("failed")
+			}
+
+			return nil
+		})
+	}
+
+	if err := apply(true); err == nil {
+		t.Fatal("expected the transaction to fail")
+	}
+
+	if data := stored(); !bytes.Equal(data, before) {
+		t.Error("expected the failed transaction to leave the store as it was")
+	}
+
+	if err := apply(false); err != nil {
+		t.Fatalf("failed to apply the transaction: %v", err)
+	}
+	defer DeleteMovie(ctx, created.Id)
+
+	var s snapshot
+	if err := json.Unmarshal(stored(), &s); err != nil {
+		t.Fatalf("invalid store: %v", err)
+	}
+
+	if !slices.ContainsFunc(s.Movies, func(movie storedMovie) bool { return movie.Id == created.Id }) {
+		t.Errorf("expected movie %d to be persisted with the transaction", created.Id)
+	}
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"maps"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	ErrTranslationNotFound = errors.New("translation not found")
+	ErrOriginalLanguage    = errors.New("translation is in the movie's original language")
+)
+
+func FindTranslations(ctx context.Context, movieId int) (models.Translations, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.FindTranslations")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("movie.id", movieId))
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	i := indexOfMovie(movieId)
+	if i == -1 {
+		return nil, ErrMovieNotFound
+	}
+
+	translations := maps.Clone(movies[i].Translations)
+	if translations == nil {
+		translations = models.Translations{}
+	}
+
+	return translations, nil
+}
+
+// add or replace the movie's translation to the language, the original
+// language is edited through the movie itself
+func SetTranslation(ctx context.Context, movieId int, language string, translation models.Translation) error {
+	_, span := tracing.Tracer.Start(ctx, "database.SetTranslation")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("movie.id", movieId),
+		attribute.String("translation.language", language),
+	)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	i := indexOfMovie(movieId)
+	if i == -1 {
+		return ErrMovieNotFound
+	}
+
+	if language == movies[i].OriginalLanguage {
+		return ErrOriginalLanguage
+	}
+
+	return mutate(func() error {
+		before := cloneMovie(movies[i])
+		if movies[i].Translations == nil {
+			movies[i].Translations = models.Translations{}
+		}
+
+		movies[i].Translations[language] = translation
+		audit(ctx, models.AuditUpdate, movieId, &before, &movies[i])
+		addEvent(ctx, models.EventMovieUpdated, movieId, &movies[i])
+		return nil
+	})
+}
+
+func DeleteTranslation(ctx context.Context, movieId int, language string) error {
+	_, span := tracing.Tracer.Start(ctx, "database.DeleteTranslation")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("movie.id", movieId),
+		attribute.String("translation.language", language),
+	)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	i := indexOfMovie(movieId)
+	if i == -1 {
+		return ErrMovieNotFound
+	}
+
+	if _, ok := movies[i].Translations[language]; !ok {
+		return ErrTranslationNotFound
+	}
+
+	return mutate(func() error {
+		before := cloneMovie(movies[i])
+		delete(movies[i].Translations, language)
+		audit(ctx, models.AuditUpdate, movieId, &before, &movies[i])
+		addEvent(ctx, models.EventMovieUpdated, movieId, &movies[i])
+		return nil
+	})
+}
@@ -0,0 +1,309 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	ErrWebhookNotFound    = errors.New("webhook not found")
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+)
+
+// the delivery log keeps the latest attempts only, dead letters are kept
+// until they're redelivered
+const maxDeliveries = 1000
+
+var (
+	webhookId    int
+	webhooks     models.Webhooks
+	deadLetterId int
+	deadLetters  []models.DeadLetter
+)
+
+// the delivery log is written on every attempt, so it has its own lock and
+// is left out of the mutations rolled back and persisted with the rest. It's
+// written to the file with the next mutation or on close.
+var (
+	deliveriesMu sync.Mutex
+	deliveryId   int
+	// a ring of the latest attempts, next is where the following one goes
+	// once it's full
+	deliveries   []models.WebhookDelivery
+	nextDelivery int
+)
+
+func indexOfWebhook(id int) int {
+	return slices.IndexFunc(webhooks, func(webhook models.Webhook) bool {
+		return webhook.Id == id
+	})
+}
+
+func cloneWebhook(webhook models.Webhook) models.Webhook {
+	webhook.Events = slices.Clone(webhook.Events)
+	return webhook
+}
+
+func FindWebhooks(ctx context.Context) models.Webhooks {
+	_, span := tracing.Tracer.Start(ctx, "database.FindWebhooks")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make(models.Webhooks, len(webhooks))
+	for i, webhook := range webhooks {
+		result[i] = cloneWebhook(webhook)
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", len(result)))
+
+	return result
+}
+
+func FindWebhookById(ctx context.Context, id int) *models.Webhook {
+	_, span := tracing.Tracer.Start(ctx, "database.FindWebhookById")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("webhook.id", id))
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	i := indexOfWebhook(id)
+	if i == -1 {
+		return nil
+	}
+
+	webhook := cloneWebhook(webhooks[i])
+	return &webhook
+}
+
+// active webhooks subscribed to the event type
+func FindSubscribers(ctx context.Context, eventType models.EventType) models.Webhooks {
+	_, span := tracing.Tracer.Start(ctx, "database.FindSubscribers")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var result models.Webhooks
+	for _, webhook := range webhooks {
+		if webhook.Wants(eventType) {
+			result = append(result, cloneWebhook(webhook))
+		}
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", len(result)))
+
+	return result
+}
+
+func CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.CreateWebhook")
+	defer span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	err := mutate(func() error {
+		webhookId++
+		webhook.Id = webhookId
+		webhook.CreatedAt = now().UTC()
+		webhooks = append(webhooks, cloneWebhook(webhook))
+		return nil
+	})
+
+	span.SetAttributes(attribute.Int("webhook.id", webhook.Id))
+
+	return webhook, err
+}
+
+// replace the webhook's settings, an empty secret keeps the current one
+func UpdateWebhook(ctx context.Context, id int, webhook models.Webhook) (models.Webhook, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.UpdateWebhook")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("webhook.id", id))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	i := indexOfWebhook(id)
+	if i == -1 {
+		return webhook, ErrWebhookNotFound
+	}
+
+	webhook.Id = id
+	webhook.CreatedAt = webhooks[i].CreatedAt
+	if webhook.Secret == "" {
+		webhook.Secret = webhooks[i].Secret
+	}
+
+	err := mutate(func() error {
+		webhooks[i] = cloneWebhook(webhook)
+		return nil
+	})
+
+	return webhook, err
+}
+
+// delete the webhook and its dead letters, which can't be redelivered anymore
+func DeleteWebhook(ctx context.Context, id int) error {
+	_, span := tracing.Tracer.Start(ctx, "database.DeleteWebhook")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("webhook.id", id))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	i := indexOfWebhook(id)
+	if i == -1 {
+		return ErrWebhookNotFound
+	}
+
+	return mutate(func() error {
+		webhooks = slices.Delete(webhooks, i, i+1)
+		deadLetters = slices.DeleteFunc(slices.Clone(deadLetters), func(letter models.DeadLetter) bool {
+			return letter.WebhookId == id
+		})
+		return nil
+	})
+}
+
+// log a delivery attempt, replacing the oldest one past maxDeliveries
+func AddDelivery(ctx context.Context, delivery models.WebhookDelivery) models.WebhookDelivery {
+	_, span := tracing.Tracer.Start(ctx, "database.AddDelivery")
+	defer span.End()
+
+	deliveriesMu.Lock()
+	defer deliveriesMu.Unlock()
+
+	deliveryId++
+	delivery.Id = deliveryId
+
+	if len(deliveries) < maxDeliveries {
+		deliveries = append(deliveries, delivery)
+	} else {
+		deliveries[nextDelivery] = delivery
+		nextDelivery = (nextDelivery + 1) % maxDeliveries
+	}
+
+	return delivery
+}
+
+// the logged delivery attempts, oldest first, and the ID of the last one
+func deliveryLog() (int, []models.WebhookDelivery) {
+	deliveriesMu.Lock()
+	defer deliveriesMu.Unlock()
+
+	return deliveryId, append(slices.Clone(deliveries[nextDelivery:]), deliveries[:nextDelivery]...)
+}
+
+// replace the delivery log with the attempts read from the file, oldest
+// first
+func restoreDeliveryLog(id int, log []models.WebhookDelivery) {
+	deliveriesMu.Lock()
+	defer deliveriesMu.Unlock()
+
+	deliveryId = id
+	deliveries = slices.Clone(log[max(len(log)-maxDeliveries, 0):])
+	nextDelivery = 0
+}
+
+// delivery attempts of the webhook, newest first
+func FindDeliveries(ctx context.Context, webhookId int) ([]models.WebhookDelivery, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.FindDeliveries")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("webhook.id", webhookId))
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if indexOfWebhook(webhookId) == -1 {
+		return nil, ErrWebhookNotFound
+	}
+
+	result := []models.WebhookDelivery{}
+	_, log := deliveryLog()
+	for _, delivery := range slices.Backward(log) {
+		if delivery.WebhookId == webhookId {
+			result = append(result, delivery)
+		}
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", len(result)))
+
+	return result, nil
+}
+
+func AddDeadLetter(ctx context.Context, letter models.DeadLetter) (models.DeadLetter, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.AddDeadLetter")
+	defer span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	err := mutate(func() error {
+		deadLetterId++
+		letter.Id = deadLetterId
+		deadLetters = append(deadLetters, letter)
+		return nil
+	})
+
+	return letter, err
+}
+
+// dead letters, newest first
+func FindDeadLetters(ctx context.Context) []models.DeadLetter {
+	_, span := tracing.Tracer.Start(ctx, "database.FindDeadLetters")
+	defer span.End()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := slices.Clone(deadLetters)
+	slices.Reverse(result)
+	if result == nil {
+		result = []models.DeadLetter{}
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", len(result)))
+
+	return result
+}
+
+// remove the dead letter so it can be redelivered, a failed redelivery adds
+// a new one
+func TakeDeadLetter(ctx context.Context, id int) (models.DeadLetter, error) {
+	_, span := tracing.Tracer.Start(ctx, "database.TakeDeadLetter")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("dead_letter.id", id))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	i := slices.IndexFunc(deadLetters, func(letter models.DeadLetter) bool {
+		return letter.Id == id
+	})
+	if i == -1 {
+		return models.DeadLetter{}, ErrDeadLetterNotFound
+	}
+
+	letter := deadLetters[i]
+	err := mutate(func() error {
+		deadLetters = slices.Delete(slices.Clone(deadLetters), i, i+1)
+		return nil
+	})
+
+	return letter, err
+}
@@ -0,0 +1,104 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// fetch every key in one lookup, keys that don't exist are left out of the
+// result
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) map[K]V
+
+// batches the lookups made while resolving one request, so resolving the
+// artists of a page of movies takes one lookup instead of one per credit.
+// Keys queued up front are fetched together with the first key that's
+// loaded, and every result is cached for the rest of the request.
+// https://github.com/graphql/dataloader
+type Loader[K comparable, V any] struct {
+	fetch BatchFunc[K, V]
+
+	mu      sync.Mutex
+	queue   []K
+	fetched map[K]bool
+	cache   map[K]V
+	batches int
+}
+
+func New[K comparable, V any](fetch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:   fetch,
+		fetched: map[K]bool{},
+		cache:   map[K]V{},
+	}
+}
+
+// queue keys to be fetched with the next batch
+func (l *Loader[K, V]) Prime(keys ...K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if !l.fetched[key] {
+			l.queue = append(l.queue, key)
+		}
+	}
+}
+
+// the value of the key, fetched together with every queued key unless it
+// already was. Loads made while a batch is fetched wait for it, so
+// concurrent resolvers share the batch rather than starting their own.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.fetched[key] {
+		l.queue = append(l.queue, key)
+		l.dispatch(ctx)
+	}
+
+	value, ok := l.cache[key]
+	return value, ok
+}
+
+// the values of the keys that exist, in the order of the keys
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) []V {
+	l.Prime(keys...)
+
+	values := make([]V, 0, len(keys))
+	for _, key := range keys {
+		if value, ok := l.Load(ctx, key); ok {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// number of lookups made so far
+func (l *Loader[K, V]) Batches() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.batches
+}
+
+// fetch the queued keys, callers must hold the lock
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	keys := make([]K, 0, len(l.queue))
+	for _, key := range l.queue {
+		if !l.fetched[key] {
+			keys = append(keys, key)
+			l.fetched[key] = true
+		}
+	}
+	l.queue = nil
+
+	if len(keys) == 0 {
+		return
+	}
+
+	l.batches++
+	for key, value := range l.fetch(ctx, keys) {
+		l.cache[key] = value
+	}
+}
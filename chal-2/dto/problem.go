@@ -0,0 +1,57 @@
+package dto
+
+// stable machine readable error codes, clients should switch on these rather
+// than on titles or details which are meant for humans
+type ErrorCode string
+
+const (
+	CodeInvalidId        ErrorCode = "INVALID_ID"
+	CodeInvalidQuery     ErrorCode = "INVALID_QUERY"
+	CodeMalformedBody    ErrorCode = "MALFORMED_BODY"
+	CodeValidationFailed ErrorCode = "VALIDATION_FAILED"
+
+	CodeMovieNotFound ErrorCode = "MOVIE_NOT_FOUND"
+	CodeMovieExists   ErrorCode = "MOVIE_ALREADY_EXISTS"
+	CodeMissingCredit ErrorCode = "MISSING_CREDITS"
+
+	CodeRevisionNotFound ErrorCode = "REVISION_NOT_FOUND"
+
+	CodeBatchTooLarge ErrorCode = "BATCH_TOO_LARGE"
+	CodeBatchAborted  ErrorCode = "BATCH_ABORTED"
+
+	CodeInvalidLanguage     ErrorCode = "INVALID_LANGUAGE"
+	CodeTranslationNotFound ErrorCode = "TRANSLATION_NOT_FOUND"
+
+	CodeArtistNotFound ErrorCode = "ARTIST_NOT_FOUND"
+	CodeArtistExists   ErrorCode = "ARTIST_ALREADY_EXISTS"
+	CodeArtistInUse    ErrorCode = "ARTIST_IN_USE"
+	CodeUnknownArtist  ErrorCode = "UNKNOWN_ARTIST"
+
+	CodeGenreNotFound ErrorCode = "GENRE_NOT_FOUND"
+	CodeGenreExists   ErrorCode = "GENRE_ALREADY_EXISTS"
+	CodeGenreInUse    ErrorCode = "GENRE_IN_USE"
+	CodeUnknownGenre  ErrorCode = "UNKNOWN_GENRE"
+
+	CodeWebhookNotFound      ErrorCode = "WEBHOOK_NOT_FOUND"
+	CodeDeadLetterNotFound   ErrorCode = "DEAD_LETTER_NOT_FOUND"
+	CodeWebhookUrlNotAllowed ErrorCode = "WEBHOOK_URL_NOT_ALLOWED"
+
+	CodeUnauthorized     ErrorCode = "UNAUTHORIZED"
+	CodeRouteNotFound    ErrorCode = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed ErrorCode = "METHOD_NOT_ALLOWED"
+	CodeInternalError    ErrorCode = "INTERNAL_ERROR"
+)
+
+// error body served as application/problem+json, code and request_id are
+// extension members
+// https://www.rfc-editor.org/rfc/rfc7807
+type Problem struct {
+	Type      string       `json:"type" example:"urn:movies-api:problem:movie-not-found"`
+	Title     string       `json:"title" example:"Movie not found"`
+	Status    int          `json:"status" example:"404"`
+	Detail    string       `json:"detail,omitempty" example:"No movie with ID 42"`
+	Instance  string       `json:"instance,omitempty" example:"/movies/42"`
+	Code      ErrorCode    `json:"code" example:"MOVIE_NOT_FOUND"`
+	RequestId string       `json:"request_id,omitempty" example:"3f9c1b0e-7d2a-4c55-9a51-0c8e2f6b1d2a"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
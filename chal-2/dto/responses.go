@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"slices"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
 type BaseResponse struct {
 	Message string `json:"message"`
 	Success bool   `json:"success"`
@@ -19,7 +25,195 @@ type PaginatedResponse[T any] struct {
 	Count int `json:"count"`
 }
 
-type ErrorResponse struct {
+// a request field that failed validation, rule is the binding tag that failed
+type FieldError struct {
+	Field   string `json:"field" example:"credits[0].role"`
+	Rule    string `json:"rule" example:"oneof"`
+	Message string `json:"message" example:"must be one of actor, director, writer, composer"`
+}
+
+type HealthCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ReadinessResponse struct {
 	BaseResponse
-	// Error string `json:"error"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// body of POST /movies and PUT /movies/{id}, artists and genres are given by
+// ID or by the name of an existing record. Artists listed without credits are
+// credited as actors in the given order.
+type MovieRequest struct {
+	Id                  int                `json:"id"`
+	Title               string             `json:"title" binding:"required,movie_title"`
+	OriginalTitle       string             `json:"original_title"`
+	Tagline             string             `json:"tagline"`
+	Description         string             `json:"description" binding:"required,notblank"`
+	Duration            int                `json:"duration" binding:"required,movie_duration"`
+	ReleaseDate         string             `json:"release_date" binding:"omitempty,datetime=2006-01-02" example:"2025-05-23"`
+	OriginalLanguage    string             `json:"original_language" binding:"omitempty,iso639_1" example:"en"`
+	ProductionCountries []string           `json:"production_countries" binding:"omitempty,dive,iso3166_1_alpha2" example:"US,ID"`
+	AgeCertification    string             `json:"age_certification" binding:"omitempty,age_certification" enums:"G,PG,PG-13,R,NC-17,SU,13+,17+,21+"`
+	Credits             []CreditRequest    `json:"credits" binding:"required_without=Artists,dive"`
+	Artists             []models.Reference `json:"artists" binding:"required_without=Credits,unique_references=artist,dive,required" swaggertype:"array,string" example:"Tom Cruise,3"`
+	Genres              []models.Reference `json:"genres" binding:"required,min=1,unique_references=genre,dive,required" swaggertype:"array,string" example:"Action,2"`
+}
+
+type CreditRequest struct {
+	Artist    models.Reference `json:"artist" binding:"required" swaggertype:"string" example:"Tom Cruise"`
+	Role      models.Role      `json:"role" binding:"required,oneof=actor director writer composer" enums:"actor,director,writer,composer"`
+	Character string           `json:"character"`
+	Order     int              `json:"order" binding:"min=0"`
+}
+
+// every credit of the movie, a plain artist list is shorthand for actors in
+// billing order
+func (request MovieRequest) AllCredits() []CreditRequest {
+	credits := slices.Clone(request.Credits)
+
+	for _, artist := range request.Artists {
+		credits = append(credits, CreditRequest{Artist: artist, Role: models.RoleActor})
+	}
+
+	return credits
+}
+
+// the movie to store, with the artists of AllCredits and the genres resolved
+// to IDs in the same order
+func (request MovieRequest) Movie(artistIds, genreIds []int) models.Movie {
+	credits := request.AllCredits()
+
+	movie := models.Movie{
+		Id:                  request.Id,
+		Title:               request.Title,
+		OriginalTitle:       request.OriginalTitle,
+		Tagline:             request.Tagline,
+		Description:         request.Description,
+		Duration:            request.Duration,
+		ReleaseDate:         request.ReleaseDate,
+		OriginalLanguage:    request.OriginalLanguage,
+		ProductionCountries: request.ProductionCountries,
+		AgeCertification:    request.AgeCertification,
+		Credits:             make(models.Credits, len(credits)),
+		GenreIds:            genreIds,
+	}
+
+	for i, credit := range credits {
+		// unordered credits keep their position in the list
+		if credit.Order == 0 {
+			credit.Order = i + 1
+		}
+
+		movie.Credits[i] = models.Credit{
+			ArtistId:  artistIds[i],
+			Role:      credit.Role,
+			Character: credit.Character,
+			Order:     credit.Order,
+		}
+	}
+
+	return movie
+}
+
+type BatchMode string
+
+const (
+	// every operation is applied or none is
+	BatchAllOrNothing BatchMode = "all_or_nothing"
+	// each operation is applied on its own, failures don't stop the rest
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+type BatchOp string
+
+const (
+	BatchCreate BatchOp = "create"
+	BatchUpdate BatchOp = "update"
+	BatchDelete BatchOp = "delete"
+)
+
+// operations are only validated as a whole here, each one is validated on
+// its own so a best effort batch can still apply the valid ones
+type BatchRequest struct {
+	Mode       BatchMode        `json:"mode" binding:"omitempty,oneof=all_or_nothing best_effort" enums:"all_or_nothing,best_effort" default:"all_or_nothing"`
+	Operations []BatchOperation `json:"operations" binding:"required,min=1"`
+}
+
+// id is the movie to update or delete, movie the body to create or update
+// it with like POST /movies and PUT /movies/{id}
+type BatchOperation struct {
+	Op    BatchOp       `json:"op" binding:"required,oneof=create update delete" enums:"create,update,delete"`
+	Id    int           `json:"id" binding:"required_unless=Op create"`
+	Movie *MovieRequest `json:"movie" binding:"required_unless=Op delete"`
+}
+
+// the outcome of one operation, status is the status code it would have had
+// as a request of its own
+type BatchResult struct {
+	Index  int           `json:"index"`
+	Op     BatchOp       `json:"op" enums:"create,update,delete"`
+	Status int           `json:"status" example:"201"`
+	Id     int           `json:"id,omitempty"`
+	Movie  *models.Movie `json:"movie,omitempty"`
+	Error  *Problem      `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	BaseResponse
+	Mode      BatchMode     `json:"mode" enums:"all_or_nothing,best_effort"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+}
+
+// active defaults to true and a missing secret is generated, on update a
+// missing secret keeps the current one
+type WebhookRequest struct {
+	Url    string             `json:"url" binding:"required,http_url" example:"https://example.com/hooks/movies"`
+	Events []models.EventType `json:"events" binding:"omitempty,unique,dive,oneof=movie.created movie.updated movie.deleted" enums:"movie.created,movie.updated,movie.deleted"`
+	Active *bool              `json:"active" example:"true"`
+	Secret string             `json:"secret" binding:"omitempty,min=16,max=256" example:"whsec_3b8f0c1e9a7d4b2c"`
+}
+
+// a movie in an artist's filmography with the roles the artist is credited for
+type ArtistMovie struct {
+	models.Movie
+	Roles models.Credits `json:"roles"`
+}
+
+// a change feed message as sent over WebSocket, type is the event type or
+// resync when the client missed events and should reload before carrying on
+type FeedMessage struct {
+	Seq   uint64        `json:"seq" example:"42"`
+	Type  string        `json:"type" enums:"movie.created,movie.updated,movie.deleted,resync"`
+	Event *models.Event `json:"event,omitempty"`
+}
+
+// body of POST /graphql
+// https://graphql.org/learn/serving-over-http/#post-request
+type GraphqlRequest struct {
+	Query         string         `json:"query" binding:"required" example:"{ movie(id: 1) { title genres { name } } }"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// errors carry the problem of the REST endpoints as extensions, e.g. the
+// code and the invalid fields
+type GraphqlResponse struct {
+	Data   any            `json:"data" swaggertype:"object"`
+	Errors []GraphqlError `json:"errors,omitempty"`
+}
+
+type GraphqlError struct {
+	Message    string            `json:"message" example:"No movie with ID 42"`
+	Locations  []GraphqlLocation `json:"locations,omitempty"`
+	Path       []any             `json:"path,omitempty" swaggertype:"array,string" example:"updateMovie"`
+	Extensions map[string]any    `json:"extensions,omitempty"`
+}
+
+type GraphqlLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
 }
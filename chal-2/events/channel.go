@@ -0,0 +1,125 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+var ErrClosed = errors.New("event bus is closed")
+
+// events published before the dispatcher catches up, publishing blocks
+// beyond that
+const channelBuffer = 256
+
+// the default bus, an in-process channel with one goroutine handing events
+// to subscribers in the order they were published. Queues make no
+// difference with a single instance.
+type ChannelBus struct {
+	// only taken by Subscribe, publishing and dispatching never wait on it
+	mu       sync.Mutex
+	handlers atomic.Pointer[[]Handler]
+	events   chan published
+	// closed by Close, the events channel stays open so a publish racing
+	// Close can't send on a closed channel
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// an event with the context it was published with, for its trace
+type published struct {
+	ctx   context.Context
+	event models.Event
+}
+
+func NewChannelBus() *ChannelBus {
+	bus := &ChannelBus{
+		events:  make(chan published, channelBuffer),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	bus.handlers.Store(&[]Handler{})
+
+	go bus.dispatch()
+
+	return bus
+}
+
+func (bus *ChannelBus) Publish(ctx context.Context, event models.Event) error {
+	select {
+	case <-bus.closing:
+		return ErrClosed
+	default:
+	}
+
+	select {
+	case bus.events <- published{context.WithoutCancel(ctx), event}:
+		return nil
+	case <-bus.closing:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (bus *ChannelBus) Subscribe(_ string, handler Handler) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	select {
+	case <-bus.closing:
+		return ErrClosed
+	default:
+	}
+
+	// copied so a dispatch holding the old slice never sees it change
+	handlers := append(append([]Handler{}, *bus.handlers.Load()...), handler)
+	bus.handlers.Store(&handlers)
+
+	return nil
+}
+
+func (bus *ChannelBus) Close(ctx context.Context) error {
+	bus.closeOnce.Do(func() {
+		close(bus.closing)
+	})
+
+	select {
+	case <-bus.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// hand events to the handlers until closed, then the ones still buffered
+func (bus *ChannelBus) dispatch() {
+	defer close(bus.done)
+
+	for {
+		select {
+		case p := <-bus.events:
+			bus.handle(p)
+		case <-bus.closing:
+			for {
+				select {
+				case p := <-bus.events:
+					bus.handle(p)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (bus *ChannelBus) handle(p published) {
+	for _, handler := range *bus.handlers.Load() {
+		handler(p.ctx, p.event)
+	}
+}
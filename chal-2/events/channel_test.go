@@ -0,0 +1,61 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/events"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+func TestChannelBusClosesWithFullBuffer(t *testing.T) {
+	bus := events.NewChannelBus()
+
+	// a handler stuck until the bus is closed, so the buffer fills up
+	release := make(chan struct{})
+	if err := bus.Subscribe("", func(context.Context, models.Event) { <-release }); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	published := make(chan error)
+	go func() {
+		for {
+			if err := bus.Publish(context.Background(), models.Event{}); err != nil {
+				published <- err
+				return
+			}
+		}
+	}()
+
+	// give the publisher time to block on the full buffer
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		closed <- bus.Close(ctx)
+	}()
+
+	select {
+	case err := <-published:
+		if !errors.Is(err, events.ErrClosed) {
+			t.Errorf("expected the blocked publish to fail with ErrClosed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the blocked publish to return")
+	}
+
+	// a subscriber can't block dispatch while the buffer is drained
+	if err := bus.Subscribe("", func(context.Context, models.Event) {}); !errors.Is(err, events.ErrClosed) {
+		t.Errorf("expected subscribing to a closed bus to fail, got %v", err)
+	}
+
+	close(release)
+
+	if err := <-closed; err != nil {
+		t.Errorf("expected the buffered events to be dispatched before closing, got %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package events
+
+import (
+	"context"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+// receives the events of a subscription, delivery is at least once so
+// handlers should be fine with seeing an event ID twice
+type Handler func(ctx context.Context, event models.Event)
+
+// fans movie events out to subscribers, published from the outbox by the
+// relay rather than by whoever changed the movie
+type EventBus interface {
+	// hand the event to the bus, once this returns the bus has it and the
+	// outbox entry can go
+	Publish(ctx context.Context, event models.Event) error
+	// call the handler for every event. With a queue, each event goes to one
+	// subscriber of the queue across every instance sharing the bus, for
+	// work that should only happen once such as webhook deliveries. Without
+	// one every subscriber gets every event.
+	Subscribe(queue string, handler Handler) error
+	// stop taking events and wait for the ones already published to reach
+	// their handlers
+	Close(ctx context.Context) error
+}
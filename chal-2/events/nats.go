@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// how long a publish waits for the server when the context has no deadline
+const flushTimeout = 5 * time.Second
+
+// publishes events to NATS under <subject>.<event type>, e.g.
+// movies.events.movie.created, so other services can subscribe to them too
+// https://docs.nats.io/nats-concepts/core-nats
+type NatsBus struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func NewNatsBus(url, subject string) (*NatsBus, error) {
+	conn, err := nats.Connect(url, nats.Name("movies-api"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &NatsBus{conn, subject}, nil
+}
+
+// core NATS doesn't acknowledge publishes, the flush waits until the server
+// has processed the message so it isn't lost with the client's buffer
+func (bus *NatsBus) Publish(ctx context.Context, event models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(bus.subject + "." + string(event.Type))
+	msg.Data = data
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(msg.Header))
+
+	if err := bus.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, flushTimeout)
+		defer cancel()
+	}
+
+	return bus.conn.FlushWithContext(ctx)
+}
+
+func (bus *NatsBus) Subscribe(queue string, handler Handler) error {
+	_, err := bus.conn.QueueSubscribe(bus.subject+".>", queue, func(msg *nats.Msg) {
+		var event models.Event
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			slog.Warn("ignoring malformed event", "subject", msg.Subject, "error", err)
+			return
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(msg.Header))
+		handler(ctx, event)
+	})
+
+	return err
+}
+
+// drain lets subscriptions handle the messages they already received before
+// the connection closes
+func (bus *NatsBus) Close(ctx context.Context) error {
+	closed := make(chan struct{})
+	bus.conn.SetClosedHandler(func(*nats.Conn) {
+		close(closed)
+	})
+
+	if err := bus.conn.Drain(); err != nil {
+		return err
+	}
+
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		bus.conn.Close()
+		return ctx.Err()
+	}
+}
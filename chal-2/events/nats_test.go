@@ -0,0 +1,358 @@
+package events_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/events"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// a stand-in NATS server speaking just enough of the client protocol for
+// publishing and (queue) subscribing, with or without headers
+// https://docs.nats.io/reference/reference-protocols/nats-protocol
+type natsServer struct {
+	listener net.Listener
+
+	mu        sync.Mutex
+	subs      []natsSub
+	published []string
+}
+
+type natsSub struct {
+	conn    net.Conn
+	subject string
+	queue   string
+	sid     string
+}
+
+func startNatsServer(t *testing.T) *natsServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server := &natsServer{listener: listener}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return server
+}
+
+func (s *natsServer) url() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *natsServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *natsServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"stand-in\",\"version\":\"2.10.0\",\"proto\":1,\"max_payload\":1048576,\"headers\":true}\r\n")
+
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "SUB":
+			// SUB <subject> [queue] <sid>
+			sub := natsSub{conn: conn, subject: fields[1], sid: fields[len(fields)-1]}
+			if len(fields) == 4 {
+				sub.queue = fields[2]
+			}
+
+			s.mu.Lock()
+			s.subs = append(s.subs, sub)
+			s.mu.Unlock()
+		case "UNSUB":
+			s.mu.Lock()
+			for i, sub := range s.subs {
+				if sub.conn == conn && sub.sid == fields[1] {
+					s.subs = append(s.subs[:i], s.subs[i+1:]...)
+					break
+				}
+			}
+			s.mu.Unlock()
+		case "PUB":
+			// PUB <subject> [reply] <size>, then the payload
+			var size int
+			fmt.Sscan(fields[len(fields)-1], &size)
+
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+
+			s.publish(fields[1], nil, payload[:size])
+		case "HPUB":
+			// HPUB <subject> [reply] <header size> <total size>, then the
+			// headers followed by the payload
+			var headerSize, size int
+			fmt.Sscan(fields[len(fields)-2], &headerSize)
+			fmt.Sscan(fields[len(fields)-1], &size)
+
+			message := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, message); err != nil {
+				return
+			}
+
+			s.publish(fields[1], message[:headerSize], message[headerSize:size])
+		}
+	}
+}
+
+// send the message to every plain subscriber and one subscriber per queue,
+// messages published with headers are delivered with them
+func (s *natsServer) publish(subject string, headers, payload []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.published = append(s.published, subject)
+	queues := map[string]bool{}
+
+	for _, sub := range s.subs {
+		if !natsMatch(sub.subject, subject) || sub.queue != "" && queues[sub.queue] {
+			continue
+		}
+
+		if sub.queue != "" {
+			queues[sub.queue] = true
+		}
+
+		if headers != nil {
+			fmt.Fprintf(sub.conn, "HMSG %s %s %d %d\r\n%s%s\r\n", subject, sub.sid, len(headers), len(headers)+len(payload), headers, payload)
+		} else {
+			fmt.Fprintf(sub.conn, "MSG %s %s %d\r\n%s\r\n", subject, sub.sid, len(payload), payload)
+		}
+	}
+}
+
+// subjects are dot separated tokens, * matches one token and > the rest
+func natsMatch(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+
+		if i >= len(subjectTokens) || token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+
+	return len(patternTokens) == len(subjectTokens)
+}
+
+// collects the events a handler receives
+type received struct {
+	mu     sync.Mutex
+	events []models.Event
+}
+
+func (r *received) handle(_ context.Context, event models.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *received) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.events)
+}
+
+// how many times the event with the given ID was received
+func (r *received) countOf(id string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, event := range r.events {
+		if event.Id == id {
+			count++
+		}
+	}
+
+	return count
+}
+
+// the latest event received, the zero event before any
+func (r *received) last() models.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.events) == 0 {
+		return models.Event{}
+	}
+
+	return r.events[len(r.events)-1]
+}
+
+func newNatsBus(t *testing.T, server *natsServer) *events.NatsBus {
+	t.Helper()
+
+	bus, err := events.NewNatsBus(server.url(), "movies.events")
+	if err != nil {
+		t.Fatalf("failed to connect to the stand-in server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		bus.Close(ctx)
+	})
+
+	return bus
+}
+
+func TestNatsBus(t *testing.T) {
+	server := startNatsServer(t)
+
+	// two instances sharing the bus
+	instances := []*events.NatsBus{newNatsBus(t, server), newNatsBus(t, server)}
+	feeds := []*received{{}, {}}
+	var deliveries received
+
+	for i, bus := range instances {
+		if err := bus.Subscribe("", feeds[i].handle); err != nil {
+			t.Fatalf("failed to subscribe: %v", err)
+		}
+
+		if err := bus.Subscribe("webhooks", deliveries.handle); err != nil {
+			t.Fatalf("failed to subscribe to the queue: %v", err)
+		}
+
+		// the subscriptions have reached the server once a publish flushes
+		if err := bus.Publish(context.Background(), models.Event{Type: "warm.up"}); err != nil {
+			t.Fatalf("failed to publish: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for feeds[0].count() < 2 || feeds[1].count() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the warm up events")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	movie := models.Movie{Id: 7, Title: "Published", GenreIds: []int{1}}
+	event := models.NewMovieEvent(models.EventMovieCreated, movie.Id, &movie)
+
+	if err := instances[0].Publish(context.Background(), event); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+
+	for _, feed := range feeds {
+		for feed.last().Id != event.Id {
+			if time.Now().After(deadline) {
+				t.Fatal("timed out waiting for every instance to receive the event")
+			}
+
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		got := feed.last()
+		if got.Type != models.EventMovieCreated || got.Movie == nil || got.Movie.Title != "Published" {
+			t.Errorf("received %+v, want the created event of movie Published", got)
+		}
+	}
+
+	// give a second delivery to the queue a chance to show up
+	time.Sleep(50 * time.Millisecond)
+
+	// the warm up events may still be arriving, only this one is counted
+	if got := deliveries.countOf(event.Id); got != 1 {
+		t.Errorf("queue subscribers received the event %d times, want once", got)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if last := server.published[len(server.published)-1]; last != "movies.events.movie.created" {
+		t.Errorf("published to %q, want movies.events.movie.created", last)
+	}
+}
+
+// the trace context travels in the message headers, so the consumer's span
+// continues the publisher's trace
+func TestNatsBusPropagatesTraces(t *testing.T) {
+	// as tracing.Setup does, the global can't be set back to the default one
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	tracer := sdktrace.NewTracerProvider().Tracer("events_test")
+
+	server := startNatsServer(t)
+	bus := newNatsBus(t, server)
+
+	consumed := make(chan [2]trace.SpanContext, 1)
+	err := bus.Subscribe("", func(ctx context.Context, event models.Event) {
+		_, span := tracer.Start(ctx, "consume")
+		defer span.End()
+
+		consumed <- [2]trace.SpanContext{trace.SpanContextFromContext(ctx), span.SpanContext()}
+	})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	ctx, span := tracer.Start(context.Background(), "publish")
+	// the subscription is sent before the publish on the same connection
+	if err := bus.Publish(ctx, models.NewMovieEvent(models.EventMovieDeleted, 7, nil)); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	span.End()
+
+	select {
+	case got := <-consumed:
+		parent, consumer := got[0], got[1]
+
+		if !parent.IsRemote() || parent.SpanID() != span.SpanContext().SpanID() {
+			t.Errorf("expected the publisher span %s as the remote parent, got %s", span.SpanContext().SpanID(), parent.SpanID())
+		}
+
+		if consumer.TraceID() != span.SpanContext().TraceID() {
+			t.Errorf("expected the consumer span in trace %s, got %s", span.SpanContext().TraceID(), consumer.TraceID())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the event")
+	}
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+)
+
+// how many outbox entries are published per round, and how often the outbox
+// is checked when no mutation signals it, e.g. after a failed publish
+const (
+	relayBatch = 100
+	relayPoll  = time.Second
+)
+
+// publish outbox events to the bus in order until stopped, an event only
+// leaves the outbox once the bus has it. The returned function stops the
+// relay after one last round.
+func StartRelay(bus EventBus) func(context.Context) error {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(relayPoll)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				relay(context.Background(), bus)
+				return
+			case <-database.OutboxSignal():
+			case <-ticker.C:
+			}
+
+			relay(context.Background(), bus)
+		}
+	}()
+
+	return func(ctx context.Context) error {
+		close(stop)
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// publish pending events until the outbox is empty or publishing fails, a
+// failed event is retried on the next poll and nothing after it is
+// published before it
+func relay(ctx context.Context, bus EventBus) {
+	for {
+		pending := database.PendingEvents(ctx, relayBatch)
+		if len(pending) == 0 {
+			return
+		}
+
+		var published []int
+		var err error
+
+		for _, entry := range pending {
+			if err = bus.Publish(entry.Context(ctx), entry.Event); err != nil {
+				slog.Error("failed to publish event", "event_id", entry.Event.Id, "error", err)
+				break
+			}
+
+			published = append(published, entry.Id)
+		}
+
+		// published twice if this fails, subscribers see the same IDs again
+		if len(published) > 0 {
+			if err := database.MarkPublished(ctx, published...); err != nil {
+				slog.Error("failed to remove published events from the outbox", "count", len(published), "error", err)
+				return
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/events"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+// a bus that is down, every publish fails
+type unavailableBus struct {
+	attempts atomic.Int32
+}
+
+func (bus *unavailableBus) Publish(context.Context, models.Event) error {
+	bus.attempts.Add(1)
+	return errors.New("bus unavailable")
+}
+
+func (bus *unavailableBus) Subscribe(string, events.Handler) error { return nil }
+
+func (bus *unavailableBus) Close(context.Context) error { return nil }
+
+func stopRelay(t *testing.T, stop func(context.Context) error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := stop(ctx); err != nil {
+		t.Fatalf("failed to stop the relay: %v", err)
+	}
+}
+
+func TestRelayKeepsEventsUntilPublished(t *testing.T) {
+	ctx := context.Background()
+	if err := database.Migrate(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	down := &unavailableBus{}
+	stop := events.StartRelay(down)
+
+	if err := database.SetTranslation(ctx, 1, "id", models.Translation{Title: "Judul"}); err != nil {
+		t.Fatalf("failed to translate movie: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for down.attempts.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the relay to publish")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stopRelay(t, stop)
+
+	pending := database.PendingEvents(ctx, 10)
+	if len(pending) != 1 || pending[0].Event.Type != models.EventMovieUpdated || pending[0].Event.MovieId != 1 {
+		t.Fatalf("outbox holds %+v, want the update of movie 1", pending)
+	}
+
+	// the bus is back, the event goes out and leaves the outbox
+	bus := events.NewChannelBus()
+	var got received
+	bus.Subscribe("", got.handle)
+
+	stopRelay(t, events.StartRelay(bus))
+
+	if err := bus.Close(ctx); err != nil {
+		t.Fatalf("failed to close the bus: %v", err)
+	}
+
+	if got.count() != 1 || got.last().Id != pending[0].Event.Id {
+		t.Errorf("received %+v, want event %s", got.events, pending[0].Event.Id)
+	}
+
+	if pending := database.PendingEvents(ctx, 10); len(pending) != 0 {
+		t.Errorf("outbox still holds %+v after publishing", pending)
+	}
+}
@@ -0,0 +1,150 @@
+package feed
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+// set from the config before anything is published
+var (
+	BufferSize = 1000
+	// how often idle streams send a keep-alive so proxies don't close them
+	Heartbeat = 15 * time.Second
+)
+
+// an event with its position in the feed, clients resume after the last
+// sequence number they saw
+type Entry struct {
+	Seq   uint64       `json:"seq" example:"42"`
+	Event models.Event `json:"event"`
+}
+
+// a client's view of the feed, C is closed when the client falls too far
+// behind or the server shuts down, either way it should reconnect and resume
+type Subscription struct {
+	C      <-chan Entry
+	c      chan Entry
+	filter func(models.Event) bool
+}
+
+// how many entries a subscriber may lag behind before it's dropped
+const subscriberBuffer = 64
+
+var (
+	mu          sync.Mutex
+	seq         uint64
+	ring        []Entry
+	subscribers = map[*Subscription]struct{}{}
+)
+
+// append the event to the ring buffer and hand it to every subscriber
+// wanting it
+func Publish(event models.Event) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	seq++
+	entry := Entry{seq, event}
+
+	ring = append(ring, entry)
+	if len(ring) > BufferSize {
+		ring = ring[len(ring)-BufferSize:]
+	}
+
+	for sub := range subscribers {
+		if !sub.filter(event) {
+			continue
+		}
+
+		select {
+		case sub.c <- entry:
+		default:
+			unsubscribe(sub)
+		}
+	}
+}
+
+// a filter of the events about any of the movies and in any of the genres,
+// no IDs match every movie or genre
+func MovieFilter(movieIds, genreIds []int) func(models.Event) bool {
+	return func(event models.Event) bool {
+		if len(movieIds) > 0 && !slices.Contains(movieIds, event.MovieId) {
+			return false
+		}
+
+		if len(genreIds) > 0 {
+			return event.Movie != nil && slices.ContainsFunc(event.Movie.GenreIds, func(id int) bool {
+				return slices.Contains(genreIds, id)
+			})
+		}
+
+		return true
+	}
+}
+
+// subscribe to events matching the filter from now on
+func Subscribe(filter func(models.Event) bool) *Subscription {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return subscribe(filter)
+}
+
+// subscribe to events matching the filter, along with the buffered ones
+// after the given sequence number. When some events after it were already
+// dropped from the buffer, or the sequence number is from before a restart,
+// there's no backlog and complete is false: the client missed events and
+// should reload, then carry on from latest.
+func Resume(after uint64, filter func(models.Event) bool) (sub *Subscription, backlog []Entry, latest uint64, complete bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	complete = after == seq || len(ring) > 0 && ring[0].Seq <= after+1 && after < seq
+	if complete {
+		for _, entry := range ring {
+			if entry.Seq > after && filter(entry.Event) {
+				backlog = append(backlog, entry)
+			}
+		}
+	}
+
+	return subscribe(filter), backlog, seq, complete
+}
+
+func subscribe(filter func(models.Event) bool) *Subscription {
+	c := make(chan Entry, subscriberBuffer)
+	sub := &Subscription{C: c, c: c, filter: filter}
+	subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+func (sub *Subscription) Close() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	unsubscribe(sub)
+}
+
+// end every subscription so streaming requests return and the server can
+// shut down
+func Close() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for sub := range subscribers {
+		unsubscribe(sub)
+	}
+}
+
+func unsubscribe(sub *Subscription) {
+	if _, ok := subscribers[sub]; !ok {
+		return
+	}
+
+	delete(subscribers, sub)
+	close(sub.c)
+}
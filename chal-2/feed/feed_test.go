@@ -0,0 +1,165 @@
+package feed
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+)
+
+// start from an empty feed holding at most size events
+func reset(t *testing.T, size int) {
+	t.Helper()
+
+	Close()
+
+	mu.Lock()
+	seq, ring = 0, nil
+	mu.Unlock()
+
+	previous := BufferSize
+	BufferSize = size
+	t.Cleanup(func() { BufferSize = previous })
+}
+
+func all(models.Event) bool { return true }
+
+func publish(movieIds ...int) {
+	for _, id := range movieIds {
+		Publish(models.NewMovieEvent(models.EventMovieUpdated, id, &models.Movie{Id: id}))
+	}
+}
+
+func seqs(entries []Entry) []uint64 {
+	result := make([]uint64, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Seq
+	}
+
+	return result
+}
+
+func TestResume(t *testing.T) {
+	reset(t, 10)
+	publish(1, 2, 3, 4, 5)
+
+	sub, backlog, latest, complete := Resume(2, all)
+	defer sub.Close()
+
+	if !complete || latest != 5 || !slices.Equal(seqs(backlog), []uint64{3, 4, 5}) {
+		t.Fatalf("expected a complete backlog of 3 to 5, got %v up to %d complete %t", seqs(backlog), latest, complete)
+	}
+
+	// the live events carry on right after the backlog
+	publish(6)
+	if entry := <-sub.C; entry.Seq != 6 {
+		t.Errorf("expected event 6 after the backlog, got %d", entry.Seq)
+	}
+
+	if len(sub.C) != 0 {
+		t.Errorf("expected no other event, got %d more", len(sub.C))
+	}
+}
+
+func TestResumeUpToDate(t *testing.T) {
+	reset(t, 10)
+	publish(1, 2)
+
+	sub, backlog, latest, complete := Resume(2, all)
+	defer sub.Close()
+
+	if !complete || latest != 2 || len(backlog) != 0 {
+		t.Errorf("expected nothing to catch up on, got %v up to %d complete %t", seqs(backlog), latest, complete)
+	}
+}
+
+func TestResumeAfterDroppedEvents(t *testing.T) {
+	reset(t, 3)
+	publish(1, 2, 3, 4, 5)
+
+	for _, after := range []uint64{
+		// 2 was dropped from the buffer
+		1,
+		// a sequence number from before a restart
+		9,
+	} {
+		sub, backlog, latest, complete := Resume(after, all)
+		sub.Close()
+
+		if complete || latest != 5 || len(backlog) != 0 {
+			t.Errorf("expected resuming after %d to need a resync to 5, got %v up to %d complete %t", after, seqs(backlog), latest, complete)
+		}
+	}
+
+	// 3 is the oldest buffered event, so nothing after 2 is missing
+	sub, backlog, _, complete := Resume(2, all)
+	sub.Close()
+
+	if !complete || !slices.Equal(seqs(backlog), []uint64{3, 4, 5}) {
+		t.Errorf("expected a complete backlog of 3 to 5, got %v complete %t", seqs(backlog), complete)
+	}
+}
+
+func TestFilters(t *testing.T) {
+	reset(t, 10)
+
+	movie := func(id int, genreIds ...int) models.Event {
+		return models.NewMovieEvent(models.EventMovieUpdated, id, &models.Movie{Id: id, GenreIds: genreIds})
+	}
+	deleted := models.NewMovieEvent(models.EventMovieDeleted, 3, nil)
+
+	for _, test := range []struct {
+		name     string
+		filter   func(models.Event) bool
+		event    models.Event
+		expected bool
+	}{
+		{"no filter", MovieFilter(nil, nil), movie(1), true},
+		{"listed movie", MovieFilter([]int{1, 2}, nil), movie(2), true},
+		{"other movie", MovieFilter([]int{1, 2}, nil), movie(3), false},
+		{"listed genre", MovieFilter(nil, []int{4}), movie(1, 3, 4), true},
+		{"other genre", MovieFilter(nil, []int{4}), movie(1, 3), false},
+		{"genre without a movie", MovieFilter(nil, []int{4}), deleted, false},
+		{"movie and genre", MovieFilter([]int{1}, []int{4}), movie(1, 4), true},
+		{"movie but other genre", MovieFilter([]int{1}, []int{4}), movie(1, 5), false},
+	} {
+		if got := test.filter(test.event); got != test.expected {
+			t.Errorf("%s: expected %t, got %t", test.name, test.expected, got)
+		}
+	}
+
+	// subscribers and backlogs only get what their filter lets through
+	publish(1, 2, 1)
+	sub, backlog, _, _ := Resume(0, MovieFilter([]int{1}, nil))
+	defer sub.Close()
+
+	publish(2, 1)
+
+	if !slices.Equal(seqs(backlog), []uint64{1, 3}) {
+		t.Errorf("expected a backlog of the events of movie 1, got %v", seqs(backlog))
+	}
+
+	if entry := <-sub.C; entry.Seq != 5 || len(sub.C) != 0 {
+		t.Errorf("expected only event 5 of movie 1 live, got %d and %d more", entry.Seq, len(sub.C))
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	reset(t, 10)
+
+	sub := Subscribe(all)
+	for range subscriberBuffer + 1 {
+		publish(1)
+	}
+
+	for range sub.C {
+	}
+
+	mu.Lock()
+	_, subscribed := subscribers[sub]
+	mu.Unlock()
+
+	if subscribed {
+		t.Error("expected the subscriber that fell behind to be dropped")
+	}
+}
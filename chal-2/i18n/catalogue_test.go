@@ -0,0 +1,189 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// calls translating a message, by the index of the message argument. The
+// calls are qualified by the package they're called from or unqualified inside
+// their own package, the arguments after the message are only formatted.
+var messageCalls = map[string]int{
+	"T":                  1,
+	"Problem":            2,
+	"NewProblem":         2,
+	"BindProblem":        2,
+	"NewBindProblem":     2,
+	"graphqlProblem":     2,
+	"graphqlBindProblem": 2,
+	"Sprintf":            1,
+	"Translate":          1,
+}
+
+// validators pick between several messages at once
+const boundMessage = "boundMessage"
+
+// functions whose returned string literals are translated messages
+var messageFuncs = map[string]bool{
+	"message":     true,
+	"typeMessage": true,
+}
+
+// every literal message the API can answer with, by the file it's used in
+func sourceMessages(t *testing.T) map[string]string {
+	t.Helper()
+
+	messages := map[string]string{}
+	add := func(fset *token.FileSet, node ast.Node) {
+		lit, ok := node.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return
+		}
+
+		message, err := strconv.Unquote(lit.Value)
+		if err == nil && message != "" {
+			messages[message] = fset.Position(lit.Pos()).String()
+		}
+	}
+
+	err := filepath.WalkDir("..", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// generated code and the client don't answer with messages
+		if entry.IsDir() && (entry.Name() == "docs" || entry.Name() == "moviepb" || entry.Name() == "client") {
+			return filepath.SkipDir
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.CallExpr:
+				if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == boundMessage {
+					for _, arg := range node.Args[1:] {
+						add(fset, arg)
+					}
+				}
+
+				if i, ok := messageArg(node.Fun); ok && i < len(node.Args) {
+					add(fset, node.Args[i])
+				}
+			case *ast.FuncDecl:
+				if messageFuncs[node.Name.Name] {
+					ast.Inspect(node.Body, func(node ast.Node) bool {
+						if ret, ok := node.(*ast.ReturnStmt); ok {
+							for _, result := range ret.Results {
+								add(fset, result)
+							}
+						}
+
+						return true
+					})
+				}
+			case *ast.ValueSpec:
+				// the problem titles
+				if len(node.Names) == 1 && node.Names[0].Name == "problemTypes" {
+					ast.Inspect(node, func(node ast.Node) bool {
+						add(fset, node)
+						return true
+					})
+				}
+			}
+
+			return true
+		})
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to parse the sources: %v", err)
+	}
+
+	return messages
+}
+
+func messageArg(fun ast.Expr) (int, bool) {
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		i, ok := messageCalls[fun.Name]
+		return i, ok
+	case *ast.SelectorExpr:
+		pkg, ok := fun.X.(*ast.Ident)
+		if !ok {
+			return 0, false
+		}
+
+		switch {
+		case pkg.Name == "i18n" && (fun.Sel.Name == "Sprintf" || fun.Sel.Name == "Translate"):
+			return 1, true
+		case pkg.Name == "utils":
+			i, ok := messageCalls[fun.Sel.Name]
+			return i, ok
+		}
+	}
+
+	return 0, false
+}
+
+func TestCataloguesAreComplete(t *testing.T) {
+	messages := sourceMessages(t)
+	if len(messages) == 0 {
+		t.Fatal("found no messages in the sources")
+	}
+
+	for tag, catalogue := range catalogues {
+		for message, position := range messages {
+			if _, ok := catalogue[message]; !ok {
+				t.Errorf("%s: no %s translation for %q", position, tag, message)
+			}
+		}
+	}
+}
+
+// the messages built from the resource name in controllers/named.go
+func TestCataloguesHaveNamedResourceMessages(t *testing.T) {
+	formats := []string{
+		"%ss found", "%s found", "%s created successfully", "%s updated successfully",
+		"%s deleted successfully", "%s ID must be an integer", "%s with the same name already exists",
+		"%s is still referenced by a movie",
+	}
+	lowerFormats := []string{
+		"No %s with ID %%d", "Invalid %s body", "Failed to create %s", "Failed to update %s",
+		"Failed to delete %s",
+	}
+
+	for tag, catalogue := range catalogues {
+		for _, kind := range []string{"artist", "genre"} {
+			title := strings.ToUpper(kind[:1]) + kind[1:]
+
+			for _, format := range formats {
+				if message := strings.Replace(format, "%s", title, 1); catalogue[message] == "" {
+					t.Errorf("no %s translation for %q", tag, message)
+				}
+			}
+
+			for _, format := range lowerFormats {
+				message := strings.Replace(strings.Replace(format, "%s", kind, 1), "%%", "%", 1)
+				if catalogue[message] == "" {
+					t.Errorf("no %s translation for %q", tag, message)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package i18n translates API messages. Messages are looked up by their
+// English text, so English needs no catalogue and a missing translation falls
+// back to the English message.
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// first one is the default for clients that accept none of them
+var Supported = []language.Tag{
+	language.English,
+	language.Indonesian,
+}
+
+var matcher = language.NewMatcher(Supported)
+
+// catalogues by language, regional variants such as id-ID may have their own
+// catalogue holding only what differs from the base language
+var catalogues = map[language.Tag]map[string]string{
+	language.Indonesian: indonesian,
+}
+
+// pick the best supported language for an Accept-Language header, e.g.
+// "id-ID,id;q=0.9,en;q=0.8" gives id
+// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Accept-Language
+func Negotiate(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Supported[0]
+	}
+
+	_, index, _ := matcher.Match(tags...)
+	return Supported[index]
+}
+
+// translate the message to the language, falling back from a regional
+// variant to its base language and then to English
+func Translate(tag language.Tag, message string) string {
+	for ; tag != language.Und; tag = tag.Parent() {
+		if translated, ok := catalogues[tag][message]; ok {
+			return translated
+		}
+	}
+
+	return message
+}
+
+// translate a format string then format it like fmt.Sprintf, arguments are
+// not translated
+func Sprintf(tag language.Tag, format string, args ...any) string {
+	format = Translate(tag, format)
+	if len(args) == 0 {
+		return format
+	}
+
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,165 @@
+package i18n
+
+// Bahasa Indonesia, keep the format verbs in the same order as the English
+// message
+var indonesian = map[string]string{
+	// responses
+	"OK":                                "OK",
+	"Ready":                             "Siap",
+	"Not ready":                         "Belum siap",
+	"Version found":                     "Versi ditemukan",
+	"Movies found":                      "Film ditemukan",
+	"Movie found":                       "Film ditemukan",
+	"Movie created successfully":        "Film berhasil dibuat",
+	"Movie updated successfully":        "Film berhasil diperbarui",
+	"Movie deleted successfully":        "Film berhasil dihapus",
+	"Artists found":                     "Artis ditemukan",
+	"Artist found":                      "Artis ditemukan",
+	"Artist created successfully":       "Artis berhasil dibuat",
+	"Artist updated successfully":       "Artis berhasil diperbarui",
+	"Artist deleted successfully":       "Artis berhasil dihapus",
+	"Genres found":                      "Genre ditemukan",
+	"Genre found":                       "Genre ditemukan",
+	"Genre created successfully":        "Genre berhasil dibuat",
+	"Genre updated successfully":        "Genre berhasil diperbarui",
+	"Genre deleted successfully":        "Genre berhasil dihapus",
+	"Batch applied":                     "Batch berhasil diterapkan",
+	"Batch partially applied":           "Batch diterapkan sebagian",
+	"Batch not applied":                 "Batch tidak diterapkan",
+	"Webhooks found":                    "Webhook ditemukan",
+	"Webhook found":                     "Webhook ditemukan",
+	"Webhook created successfully":      "Webhook berhasil dibuat",
+	"Webhook updated successfully":      "Webhook berhasil diperbarui",
+	"Webhook deleted successfully":      "Webhook berhasil dihapus",
+	"Deliveries found":                  "Pengiriman ditemukan",
+	"Dead letters found":                "Dead letter ditemukan",
+	"Dead letter queued for redelivery": "Dead letter diantrekan untuk dikirim ulang",
+	"Audit entries found":               "Entri audit ditemukan",
+	"Revisions found":                   "Revisi ditemukan",
+	"Revision diff found":               "Perbedaan revisi ditemukan",
+	"Revision restored successfully":    "Revisi berhasil dipulihkan",
+	"Translations found":                "Terjemahan ditemukan",
+	"Translation saved successfully":    "Terjemahan berhasil disimpan",
+	"Translation deleted successfully":  "Terjemahan berhasil dihapus",
+
+	// problem titles
+	"Invalid ID":              "ID tidak valid",
+	"Invalid query parameter": "Parameter kueri tidak valid",
+	"Malformed request body":  "Isi permintaan tidak dapat dibaca",
+	"Validation failed":       "Validasi gagal",
+	"Movie not found":         "Film tidak ditemukan",
+	"Movie already exists":    "Film sudah ada",
+	"Movie has no credits":    "Film tidak memiliki kredit",
+	"Artist not found":        "Artis tidak ditemukan",
+	"Artist already exists":   "Artis sudah ada",
+	"Artist is in use":        "Artis sedang digunakan",
+	"Unknown artist":          "Artis tidak dikenal",
+	"Genre not found":         "Genre tidak ditemukan",
+	"Genre already exists":    "Genre sudah ada",
+	"Genre is in use":         "Genre sedang digunakan",
+	"Unknown genre":           "Genre tidak dikenal",
+	"Unauthorized":            "Tidak terautentikasi",
+	"Revision not found":      "Revisi tidak ditemukan",
+	"Invalid language":        "Bahasa tidak valid",
+	"Translation not found":   "Terjemahan tidak ditemukan",
+	"Batch too large":         "Batch terlalu besar",
+	"Batch aborted":           "Batch dibatalkan",
+	"Webhook not found":       "Webhook tidak ditemukan",
+	"Webhook URL not allowed": "URL webhook tidak diizinkan",
+	"Dead letter not found":   "Dead letter tidak ditemukan",
+	"Route not found":         "Rute tidak ditemukan",
+	"Method not allowed":      "Metode tidak diizinkan",
+	"Internal server error":   "Terjadi kesalahan pada server",
+
+	// problem details
+	"Movie ID must be an integer":                   "ID film harus berupa bilangan bulat",
+	"Artist ID must be an integer":                  "ID artis harus berupa bilangan bulat",
+	"Genre ID must be an integer":                   "ID genre harus berupa bilangan bulat",
+	"No movie with ID %d":                           "Tidak ada film dengan ID %d",
+	"No artist with ID %d":                          "Tidak ada artis dengan ID %d",
+	"No genre with ID %d":                           "Tidak ada genre dengan ID %d",
+	"No artist %q":                                  "Tidak ada artis %q",
+	"No genre %q":                                   "Tidak ada genre %q",
+	"Invalid movie body":                            "Isi film tidak valid",
+	"Invalid artist body":                           "Isi artis tidak valid",
+	"Invalid genre body":                            "Isi genre tidak valid",
+	"Movie must credit at least one artist":         "Film harus mengkreditkan setidaknya satu artis",
+	"Movie with updated ID already exists":          "Film dengan ID baru sudah ada",
+	"Artist with the same name already exists":      "Artis dengan nama yang sama sudah ada",
+	"Genre with the same name already exists":       "Genre dengan nama yang sama sudah ada",
+	"Artist is still referenced by a movie":         "Artis masih dirujuk oleh sebuah film",
+	"Genre is still referenced by a movie":          "Genre masih dirujuk oleh sebuah film",
+	"Failed to create movie":                        "Gagal membuat film",
+	"Failed to update movie":                        "Gagal memperbarui film",
+	"Failed to delete movie":                        "Gagal menghapus film",
+	"Failed to create artist":                       "Gagal membuat artis",
+	"Failed to update artist":                       "Gagal memperbarui artis",
+	"Failed to delete artist":                       "Gagal menghapus artis",
+	"Failed to create genre":                        "Gagal membuat genre",
+	"Failed to update genre":                        "Gagal memperbarui genre",
+	"Failed to delete genre":                        "Gagal menghapus genre",
+	"Unknown sort %q":                               "Urutan %q tidak dikenal",
+	"Revision number must be an integer":            "Nomor revisi harus berupa bilangan bulat",
+	"Movie %d has no such revision":                 "Film %d tidak memiliki revisi tersebut",
+	"Failed to restore revision":                    "Gagal memulihkan revisi",
+	"%s must be an integer":                         "%s harus berupa bilangan bulat",
+	"%s must be a list of integers":                 "%s harus berupa daftar bilangan bulat",
+	"%s must be an RFC 3339 time":                   "%s harus berupa waktu RFC 3339",
+	"The bearer token is invalid or expired":        "Token bearer tidak valid atau kedaluwarsa",
+	"A bearer token is required":                    "Token bearer diperlukan",
+	"Webhook URL %q must point to a public address": "URL webhook %q harus mengarah ke alamat publik",
+	"%s must be a year":                             "%s harus berupa tahun",
+	"Unknown %s value %q, expected any of %s":       "Nilai %s %q tidak dikenal, gunakan salah satu dari %s",
+	"Failed to read movies":                         "Gagal membaca film",
+	"No route for %s %s":                            "Tidak ada rute untuk %s %s",
+	"%s is not allowed on %s":                       "%s tidak diizinkan pada %s",
+	"Invalid batch body":                            "Isi batch tidak valid",
+	"Invalid operation":                             "Operasi tidak valid",
+	"A batch may have at most %d operations":        "Batch hanya boleh berisi paling banyak %d operasi",
+	"Failed to apply operation":                     "Gagal menerapkan operasi",
+	"Not applied because operation %d failed":       "Tidak diterapkan karena operasi %d gagal",
+	"Webhook ID must be an integer":                 "ID webhook harus berupa bilangan bulat",
+	"Dead letter ID must be an integer":             "ID dead letter harus berupa bilangan bulat",
+	"No webhook with ID %d":                         "Tidak ada webhook dengan ID %d",
+	"No dead letter with ID %d":                     "Tidak ada dead letter dengan ID %d",
+	"Invalid webhook body":                          "Isi webhook tidak valid",
+	"Failed to create webhook":                      "Gagal membuat webhook",
+	"Failed to update webhook":                      "Gagal memperbarui webhook",
+	"Failed to delete webhook":                      "Gagal menghapus webhook",
+	"Failed to redeliver dead letter":               "Gagal mengirim ulang dead letter",
+	"Invalid GraphQL request":                       "Permintaan GraphQL tidak valid",
+	"Language %q must be an ISO 639-1 code":         "Bahasa %q harus berupa kode ISO 639-1",
+
+	"Invalid translation body":                              "Isi terjemahan tidak valid",
+	"Failed to save translation":                            "Gagal menyimpan terjemahan",
+	"Failed to delete translation":                          "Gagal menghapus terjemahan",
+	"Movie %d has no %s translation":                        "Film %d tidak memiliki terjemahan %s",
+	"The movie is originally in %s, edit the movie instead": "Film ini aslinya berbahasa %s, ubah filmnya saja",
+
+	// validation errors
+	"is required":                                                     "wajib diisi",
+	"is required unless %s is %s":                                     "wajib diisi kecuali %s bernilai %s",
+	"is required when %s is not given":                                "wajib diisi jika %s tidak diberikan",
+	"must not be blank":                                               "tidak boleh kosong",
+	"must be at least %s":                                             "minimal %s",
+	"must be at least %s characters":                                  "minimal %s karakter",
+	"must have at least %s items":                                     "minimal berisi %s item",
+	"must be at least %s minutes":                                     "minimal %s menit",
+	"must be at most %s":                                              "maksimal %s",
+	"must be at most %s characters":                                   "maksimal %s karakter",
+	"must have at most %s items":                                      "maksimal berisi %s item",
+	"must be at most %s minutes":                                      "maksimal %s menit",
+	"must be one of %s":                                               "harus salah satu dari %s",
+	"must be a date formatted as YYYY-MM-DD":                          "harus berupa tanggal dengan format YYYY-MM-DD",
+	"must be a two letter ISO 639-1 language code":                    "harus berupa kode bahasa ISO 639-1 dua huruf",
+	"must be an HTTP or HTTPS URL":                                    "harus berupa URL HTTP atau HTTPS",
+	"must be a two letter ISO 3166-1 country code":                    "harus berupa kode negara ISO 3166-1 dua huruf",
+	"must not list the same item twice":                               "tidak boleh mencantumkan item yang sama dua kali",
+	"must not credit an artist for the same role and character twice": "tidak boleh mengkreditkan artis untuk peran dan karakter yang sama dua kali",
+	"is invalid":                                                      "tidak valid",
+	"must be a string":                                                "harus berupa teks",
+	"must be a list":                                                  "harus berupa daftar",
+	"must be an object":                                               "harus berupa objek",
+	"must be a boolean":                                               "harus berupa boolean",
+	"must be a number":                                                "harus berupa angka",
+}
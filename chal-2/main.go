@@ -8,10 +8,30 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/config"
+	"github.com/sglkc/roketin-be-test/chal-2/controllers"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/events"
+	"github.com/sglkc/roketin-be-test/chal-2/feed"
+	"github.com/sglkc/roketin-be-test/chal-2/middlewares"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
 	"github.com/sglkc/roketin-be-test/chal-2/routes"
+	"github.com/sglkc/roketin-be-test/chal-2/rpc"
+	"github.com/sglkc/roketin-be-test/chal-2/server"
+	"github.com/sglkc/roketin-be-test/chal-2/shutdown"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+	"github.com/sglkc/roketin-be-test/chal-2/webhooks"
 )
 
 // @title			Movies API
@@ -21,12 +41,122 @@ import (
 // @contact.url	https://github.com/sglkc/roketin-be-test
 // @produce		json
 // @accept			json
+// @BasePath		/v1
 func main() {
-	router := gin.Default()
+	cfg, err := config.Load(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		os.Exit(2)
+	}
 
+	slog.SetDefault(utils.NewLogger(cfg.LogLevel))
+	gin.SetMode(cfg.GinMode)
+	utils.DefaultLimit = cfg.Pagination.DefaultLimit
+	utils.MaxLimit = cfg.Pagination.MaxLimit
+	controllers.MaxBatchSize = cfg.Batch.MaxSize
+	webhooks.MaxAttempts = cfg.Webhooks.MaxAttempts
+	webhooks.InitialBackoff = cfg.Webhooks.InitialBackoff
+	webhooks.MaxBackoff = cfg.Webhooks.MaxBackoff
+	webhooks.Timeout = cfg.Webhooks.Timeout
+	webhooks.AllowedHosts = cfg.Webhooks.AllowedHosts
+	feed.BufferSize = cfg.Events.BufferSize
+	feed.Heartbeat = cfg.Events.Heartbeat
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TraceExporter)
+	if err != nil {
+		slog.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	shutdown.Register("tracing", shutdownTracing)
+
+	if err := database.Open(cfg.Storage.Backend, cfg.Storage.DSN); err != nil {
+		slog.Error("failed to open database", "error", err)
+		os.Exit(1)
+	}
+
+	if err := database.Migrate(context.Background()); err != nil {
+		slog.Error("failed to migrate database", "error", err)
+		os.Exit(1)
+	}
+
+	// registered after the database so pending deliveries finish before it's
+	// flushed
+	shutdown.Register("webhooks", webhooks.Start(cfg.Webhooks.Workers))
+
+	bus, err := newEventBus(cfg)
+	if err != nil {
+		slog.Error("failed to set up event bus", "bus", cfg.Events.Bus, "error", err)
+		os.Exit(1)
+	}
+	shutdown.Register("event bus", bus.Close)
+
+	// webhooks are delivered by one instance, every instance feeds its own
+	// change feed clients
+	subscriptions := map[string]events.Handler{
+		"webhooks": webhooks.Publish,
+		"": func(_ context.Context, event models.Event) {
+			feed.Publish(event)
+		},
+	}
+	for queue, handler := range subscriptions {
+		if err := bus.Subscribe(queue, handler); err != nil {
+			slog.Error("failed to subscribe to events", "queue", queue, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// stopped first so the outbox is relayed once more before the bus closes
+	shutdown.Register("outbox relay", events.StartRelay(bus))
+
+	if cfg.Grpc.Addr != "" {
+		stopGrpc, err := rpc.Start(cfg.Grpc.Addr, cfg.Auth.Secret, cfg.Tls.CertFile, cfg.Tls.KeyFile)
+		if err != nil {
+			slog.Error("failed to start gRPC server", "error", err)
+			os.Exit(1)
+		}
+		shutdown.Register("grpc", stopGrpc)
+	}
+
+	router := gin.New()
+	router.Use(
+		middlewares.RequestId(),
+		middlewares.Locale(),
+		middlewares.Tracing(),
+		middlewares.Logger(),
+		middlewares.Recovery(),
+		middlewares.Cors(cfg.Cors.AllowedOrigins),
+		middlewares.Auth(cfg.Auth.Secret),
+	)
+
+	routes.RegisterErrorRoutes(router)
+	routes.RegisterMetricsRoutes(router)
+	routes.RegisterHealthRoutes(router)
 	routes.RegisterSwaggerRoutes(router)
-	routes.RegisterMovieRoutes(router)
+	routes.RegisterV1Routes(router.Group("/v1"))
+	routes.RegisterUnversionedRoutes(router, cfg.UnversionedRoutes.Deprecation, cfg.UnversionedRoutes.Sunset)
+	routes.RegisterGraphqlRoutes(router)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	srv := server.New(cfg, router)
+	// event streams only end when told to, otherwise they'd hold up the
+	// shutdown until it times out
+	srv.RegisterOnShutdown(feed.Close)
+
+	if err := server.Run(ctx, srv, cfg); err != nil {
+		slog.Error("server exited with error", "error", err)
+		os.Exit(1)
+	}
+}
+
+func newEventBus(cfg *config.Config) (events.EventBus, error) {
+	if cfg.Events.Bus == "nats" {
+		return events.NewNatsBus(cfg.Events.NatsUrl, cfg.Events.NatsSubject)
+	}
 
-	log.Println("Running at localhost:8080 (docs at http://localhost:8080/swagger/index.html)")
-	router.Run("localhost:8080")
+	return events.NewChannelBus(), nil
 }
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+)
+
+// https://prometheus.io/docs/guides/go-application/
+// collectors are registered to the default registry, which already exposes
+// the Go runtime and process stats
+
+const namespace = "movies_api"
+
+var HttpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "http_requests_total",
+	Help:      "Total number of HTTP requests by method, route and status.",
+}, []string{"method", "route", "status"})
+
+var HttpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "http_request_duration_seconds",
+	Help:      "HTTP request latency by method, route and status.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route", "status"})
+
+var MoviesStored = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "movies_stored",
+	Help:      "Number of movies currently in the store.",
+}, func() float64 {
+	return float64(database.CountMovies(context.Background()))
+})
+
+var SearchResults = promauto.NewHistogram(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "search_results",
+	Help:      "Number of movies matched by a search before pagination.",
+	Buckets:   []float64{0, 1, 5, 10, 25, 50, 100, 250, 500, 1000},
+})
+
+var PaginationLimit = promauto.NewHistogram(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "pagination_limit",
+	Help:      "Page size requested by paginated endpoints.",
+	Buckets:   []float64{1, 5, 10, 20, 50, 100},
+})
+
+var WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "webhook_deliveries_total",
+	Help:      "Total number of webhook delivery attempts by event type and status.",
+}, []string{"event", "status"})
@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// identify the actor making the request from a bearer JWT signed with the
+// auth secret, the subject claim names the actor. Requests without a token
+// stay anonymous and an invalid token is rejected. Without a secret no token
+// can be verified, so every request is anonymous.
+func Auth(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || secret == "" {
+			setActor(c, database.AnonymousActor)
+			c.Next()
+			return
+		}
+
+		actor, err := utils.TokenActor(secret, token)
+		if err != nil {
+			utils.Logger(c).Warn("invalid auth token", "error", err)
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			utils.Problem(c, dto.CodeUnauthorized, "The bearer token is invalid or expired")
+			return
+		}
+
+		setActor(c, actor)
+		c.Next()
+	}
+}
+
+func setActor(c *gin.Context, actor string) {
+	c.Set(utils.ActorKey, actor)
+	c.Request = c.Request.WithContext(database.WithActor(c.Request.Context(), actor))
+}
+
+// reject anonymous requests, for routes that only authenticated actors may
+// use. Runs after Auth.
+func RequireActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(utils.ActorKey) == database.AnonymousActor {
+			c.Header("WWW-Authenticate", "Bearer")
+			utils.Problem(c, dto.CodeUnauthorized, "A bearer token is required")
+			return
+		}
+
+		c.Next()
+	}
+}
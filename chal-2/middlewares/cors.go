@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// https://developer.mozilla.org/en-US/docs/Web/HTTP/CORS
+// no origins means CORS is disabled and browsers fall back to same-origin
+func Cors(allowedOrigins []string) gin.HandlerFunc {
+	allowAny := slices.Contains(allowedOrigins, "*")
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+
+		if origin == "" || (!allowAny && !slices.Contains(allowedOrigins, origin)) {
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Origin", origin)
+		c.Header("Access-Control-Expose-Headers", RequestIdHeader+", Deprecation, Sunset, Link")
+		c.Writer.Header().Add("Vary", "Origin")
+
+		// answer preflight requests here, the router has no OPTIONS routes
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Authorization, Content-Type, Last-Event-ID, "+RequestIdHeader)
+			c.Header("Access-Control-Max-Age", "600")
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mark responses as deprecated since deprecation, pointing to the same path
+// and query under successor, e.g. /v1. The sunset is when the routes may stop
+// responding, none is announced when it's zero. Nothing is marked before a
+// deprecation date is set.
+// https://www.rfc-editor.org/rfc/rfc9745
+// https://www.rfc-editor.org/rfc/rfc8594
+func Deprecated(deprecation, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecationValue := "@" + strconv.FormatInt(deprecation.Unix(), 10)
+	sunsetValue := sunset.UTC().Format(http.TimeFormat)
+
+	return func(c *gin.Context) {
+		if deprecation.IsZero() {
+			c.Next()
+			return
+		}
+
+		c.Header("Deprecation", deprecationValue)
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunsetValue)
+		}
+		link := successor + c.Request.URL.Path
+		if c.Request.URL.RawQuery != "" {
+			link += "?" + c.Request.URL.RawQuery
+		}
+		c.Header("Link", "<"+link+`>; rel="successor-version"`)
+
+		c.Next()
+	}
+}
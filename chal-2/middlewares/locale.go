@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/i18n"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// negotiate the response language from Accept-Language, caches must keep
+// responses apart by the header since the body depends on it
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tag := i18n.Negotiate(c.GetHeader("Accept-Language"))
+
+		c.Set(utils.LocaleKey, tag)
+		c.Header("Content-Language", tag.String())
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/metrics"
+)
+
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		// label by route template to keep cardinality bounded, unmatched
+		// routes are grouped together instead of using the raw path
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HttpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HttpRequestDuration.WithLabelValues(c.Request.Method, route, status).
+			Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,16 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// gin.Recovery answers panics with an empty 500, answer with a problem like
+// every other error instead
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		utils.Logger(c).Error("request panicked", "panic", recovered)
+		utils.Problem(c, dto.CodeInternalError, "")
+	})
+}
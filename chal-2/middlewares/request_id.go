@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+const RequestIdHeader = "X-Request-ID"
+
+// reuse the request ID sent by the client or upstream proxy, otherwise
+// generate a new one, and echo it back in the response header
+func RequestId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(RequestIdHeader)
+
+		if requestId == "" || len(requestId) > 128 {
+			requestId = newRequestId()
+		}
+
+		c.Set(utils.RequestIdKey, requestId)
+		c.Header(RequestIdHeader, requestId)
+		c.Next()
+	}
+}
+
+func newRequestId() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+
+	return hex.EncodeToString(bytes)
+}
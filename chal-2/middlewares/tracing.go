@@ -0,0 +1,14 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// https://pkg.go.dev/go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin
+// starts a server span per request named after the route template, handlers
+// create child spans from c.Request.Context()
+func Tracing() gin.HandlerFunc {
+	return otelgin.Middleware(tracing.ServiceName)
+}
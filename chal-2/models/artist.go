@@ -0,0 +1,8 @@
+package models
+
+type Artist struct {
+	Id   int    `json:"id"`
+	Name string `json:"name" binding:"required,notblank"`
+}
+
+type Artists []Artist
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// one mutation of a movie, entries are only ever appended
+type AuditEntry struct {
+	Id        int           `json:"id"`
+	Actor     string        `json:"actor" example:"alice"`
+	Timestamp time.Time     `json:"timestamp"`
+	Action    AuditAction   `json:"action" enums:"create,update,delete"`
+	MovieId   int           `json:"movie_id"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// a movie field before and after the mutation, null when it didn't exist
+type FieldChange struct {
+	Field  string `json:"field" example:"title"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
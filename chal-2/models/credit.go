@@ -0,0 +1,36 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+)
+
+type Role string
+
+const (
+	RoleActor    Role = "actor"
+	RoleDirector Role = "director"
+	RoleWriter   Role = "writer"
+	RoleComposer Role = "composer"
+)
+
+// every role an artist can be credited with
+var Roles = []Role{RoleActor, RoleDirector, RoleWriter, RoleComposer}
+
+// an artist credited in a movie, the same artist may be credited more than
+// once with different roles
+type Credit struct {
+	ArtistId  int    `json:"artist_id"`
+	Role      Role   `json:"role" enums:"actor,director,writer,composer"`
+	Character string `json:"character,omitempty"`
+	Order     int    `json:"order"`
+}
+
+type Credits []Credit
+
+// sort by billing order, keeping the given order for equal ones
+func (credits Credits) Sort() {
+	slices.SortStableFunc(credits, func(a, b Credit) int {
+		return cmp.Compare(a.Order, b.Order)
+	})
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventMovieCreated EventType = "movie.created"
+	EventMovieUpdated EventType = "movie.updated"
+	EventMovieDeleted EventType = "movie.deleted"
+)
+
+var EventTypes = []EventType{EventMovieCreated, EventMovieUpdated, EventMovieDeleted}
+
+// something that happened to a movie, with the movie after the change or as
+// it was before a deletion
+type Event struct {
+	Id         string    `json:"id" example:"9f2c4e1a6b3d4f5e8a7b6c5d4e3f2a1b"`
+	Type       EventType `json:"type" enums:"movie.created,movie.updated,movie.deleted"`
+	OccurredAt time.Time `json:"occurred_at"`
+	MovieId    int       `json:"movie_id"`
+	Movie      *Movie    `json:"movie,omitempty"`
+}
+
+func NewMovieEvent(eventType EventType, movieId int, movie *Movie) Event {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return Event{
+		Id:         hex.EncodeToString(id),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		MovieId:    movieId,
+		Movie:      movie,
+	}
+}
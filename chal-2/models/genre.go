@@ -0,0 +1,8 @@
+package models
+
+type Genre struct {
+	Id   int    `json:"id"`
+	Name string `json:"name" binding:"required,notblank"`
+}
+
+type Genres []Genre
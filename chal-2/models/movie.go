@@ -1,14 +1,71 @@
 package models
 
+import "strconv"
+
 // https://gin-gonic.com/en/docs/examples/binding-and-validation/
 // https://pkg.go.dev/github.com/go-playground/validator/v10
 type Movie struct {
-	Id          int      `json:"id"`
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Duration    int      `json:"duration" binding:"required,min=1"`
-	Artists     []string `json:"artists" binding:"required,min=1"`
-	Genres      []string `json:"genres" binding:"required,min=1"`
+	Id                  int      `json:"id"`
+	Title               string   `json:"title" binding:"required"`
+	OriginalTitle       string   `json:"original_title,omitempty"`
+	Tagline             string   `json:"tagline,omitempty"`
+	Description         string   `json:"description" binding:"required"`
+	Duration            int      `json:"duration" binding:"required,min=1"`
+	ReleaseDate         string   `json:"release_date,omitempty" example:"2025-05-23"`
+	OriginalLanguage    string   `json:"original_language,omitempty" example:"en"`
+	ProductionCountries []string `json:"production_countries,omitempty" example:"US,ID"`
+	AgeCertification    string   `json:"age_certification,omitempty" example:"PG-13"`
+	Credits             Credits  `json:"credits"`
+	GenreIds            []int    `json:"genre_ids"`
+
+	// stored translations, read through /movies/{id}/translations
+	Translations Translations `json:"translations,omitempty" swaggerignore:"true"`
+	// language the title, description and tagline of a response are in
+	Language string `json:"language,omitempty" example:"id"`
 }
 
 type Movies []Movie
+
+// release year or 0 when the release date is unknown
+func (movie Movie) ReleaseYear() int {
+	if len(movie.ReleaseDate) < 4 {
+		return 0
+	}
+
+	year, _ := strconv.Atoi(movie.ReleaseDate[:4])
+	return year
+}
+
+// the movie with its text in the first of the preferred languages it has,
+// falling back field by field to the original. No match gives the original.
+func (movie Movie) Localize(preferred ...string) Movie {
+	movie.Language = movie.OriginalLanguage
+
+	for _, language := range preferred {
+		if language == movie.OriginalLanguage {
+			break
+		}
+
+		translation, ok := movie.Translations[language]
+		if !ok {
+			continue
+		}
+
+		movie.Language = language
+		movie.Title = translation.Title
+
+		if translation.Description != "" {
+			movie.Description = translation.Description
+		}
+
+		if translation.Tagline != "" {
+			movie.Tagline = translation.Tagline
+		}
+
+		break
+	}
+
+	movie.Translations = nil
+
+	return movie
+}
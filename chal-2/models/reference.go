@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// refers to an artist or genre either by ID (a JSON number) or by name (a
+// JSON string), names are resolved to existing records
+type Reference struct {
+	Id   int
+	Name string
+}
+
+func (r *Reference) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &r.Id); err == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &r.Name); err == nil {
+		return nil
+	}
+
+	return errors.New("reference must be an ID or a name")
+}
+
+func (r Reference) MarshalJSON() ([]byte, error) {
+	if r.Id != 0 {
+		return json.Marshal(r.Id)
+	}
+
+	return json.Marshal(r.Name)
+}
+
+func (r Reference) String() string {
+	if r.Id != 0 {
+		return strconv.Itoa(r.Id)
+	}
+
+	return r.Name
+}
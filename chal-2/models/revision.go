@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// a previous version of a movie, kept when an update replaced it. Actor and
+// timestamp tell who replaced it and when.
+type Revision struct {
+	Revision  int       `json:"revision"`
+	Actor     string    `json:"actor" example:"alice"`
+	Timestamp time.Time `json:"timestamp"`
+	Movie     Movie     `json:"movie"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// title, description and tagline of a movie in another language, empty
+// fields fall back to the original
+type Translation struct {
+	Title       string `json:"title" binding:"required,movie_title" example:"Misi: Mustahil - Perhitungan Terakhir"`
+	Description string `json:"description,omitempty"`
+	Tagline     string `json:"tagline,omitempty"`
+}
+
+// translations by ISO 639-1 language code
+type Translations map[string]Translation
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// a subscription to movie events, events lists the event types to deliver
+// and is empty to deliver all of them. The secret signs deliveries and is
+// only shown when the webhook is created
+type Webhook struct {
+	Id        int         `json:"id"`
+	Url       string      `json:"url" example:"https://example.com/hooks/movies"`
+	Events    []EventType `json:"events" enums:"movie.created,movie.updated,movie.deleted"`
+	Active    bool        `json:"active"`
+	Secret    string      `json:"secret,omitempty" example:"whsec_3b8f0c1e9a7d4b2c"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type Webhooks []Webhook
+
+// whether the webhook should receive the event type
+func (webhook Webhook) Wants(eventType EventType) bool {
+	if !webhook.Active {
+		return false
+	}
+
+	if len(webhook.Events) == 0 {
+		return true
+	}
+
+	for _, wanted := range webhook.Events {
+		if wanted == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// one attempt at delivering an event to a webhook
+type WebhookDelivery struct {
+	Id         int            `json:"id"`
+	WebhookId  int            `json:"webhook_id"`
+	EventId    string         `json:"event_id"`
+	EventType  EventType      `json:"event_type"`
+	Attempt    int            `json:"attempt"`
+	Status     DeliveryStatus `json:"status" enums:"succeeded,failed"`
+	StatusCode int            `json:"status_code,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	DurationMs int64          `json:"duration_ms"`
+	Timestamp  time.Time      `json:"timestamp"`
+}
+
+// an event that still failed after every attempt, kept so it can be
+// redelivered once the receiver is fixed
+type DeadLetter struct {
+	Id        int       `json:"id"`
+	WebhookId int       `json:"webhook_id"`
+	Event     Event     `json:"event"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
+}
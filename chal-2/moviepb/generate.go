@@ -0,0 +1,5 @@
+// Package moviepb holds the protobuf messages and gRPC stubs of the movie
+// service, regenerate them after changing movie.proto
+package moviepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative movie.proto
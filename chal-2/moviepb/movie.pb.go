@@ -0,0 +1,1524 @@
+// The movie API over gRPC, for internal services. It serves the same
+// catalogue as the REST API with the same validation and errors: the status
+// has an ErrorInfo detail whose reason is the REST error code, e.g.
+// MOVIE_NOT_FOUND, and a BadRequest detail listing invalid fields.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: movie.proto
+
+package moviepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Role int32
+
+const (
+	Role_ROLE_UNSPECIFIED Role = 0
+	Role_ROLE_ACTOR       Role = 1
+	Role_ROLE_DIRECTOR    Role = 2
+	Role_ROLE_WRITER      Role = 3
+	Role_ROLE_COMPOSER    Role = 4
+)
+
+// Enum value maps for Role.
+var (
+	Role_name = map[int32]string{
+		0: "ROLE_UNSPECIFIED",
+		1: "ROLE_ACTOR",
+		2: "ROLE_DIRECTOR",
+		3: "ROLE_WRITER",
+		4: "ROLE_COMPOSER",
+	}
+	Role_value = map[string]int32{
+		"ROLE_UNSPECIFIED": 0,
+		"ROLE_ACTOR":       1,
+		"ROLE_DIRECTOR":    2,
+		"ROLE_WRITER":      3,
+		"ROLE_COMPOSER":    4,
+	}
+)
+
+func (x Role) Enum() *Role {
+	p := new(Role)
+	*p = x
+	return p
+}
+
+func (x Role) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Role) Descriptor() protoreflect.EnumDescriptor {
+	return file_movie_proto_enumTypes[0].Descriptor()
+}
+
+func (Role) Type() protoreflect.EnumType {
+	return &file_movie_proto_enumTypes[0]
+}
+
+func (x Role) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Role.Descriptor instead.
+func (Role) EnumDescriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{0}
+}
+
+type MovieEvent_Type int32
+
+const (
+	MovieEvent_TYPE_UNSPECIFIED MovieEvent_Type = 0
+	MovieEvent_TYPE_CREATED     MovieEvent_Type = 1
+	MovieEvent_TYPE_UPDATED     MovieEvent_Type = 2
+	MovieEvent_TYPE_DELETED     MovieEvent_Type = 3
+	// events after after_seq were missed, reload before carrying on
+	MovieEvent_TYPE_RESYNC MovieEvent_Type = 4
+)
+
+// Enum value maps for MovieEvent_Type.
+var (
+	MovieEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_CREATED",
+		2: "TYPE_UPDATED",
+		3: "TYPE_DELETED",
+		4: "TYPE_RESYNC",
+	}
+	MovieEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_CREATED":     1,
+		"TYPE_UPDATED":     2,
+		"TYPE_DELETED":     3,
+		"TYPE_RESYNC":      4,
+	}
+)
+
+func (x MovieEvent_Type) Enum() *MovieEvent_Type {
+	p := new(MovieEvent_Type)
+	*p = x
+	return p
+}
+
+func (x MovieEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MovieEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_movie_proto_enumTypes[1].Descriptor()
+}
+
+func (MovieEvent_Type) Type() protoreflect.EnumType {
+	return &file_movie_proto_enumTypes[1]
+}
+
+func (x MovieEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MovieEvent_Type.Descriptor instead.
+func (MovieEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{14, 0}
+}
+
+type Movie struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	OriginalTitle string                 `protobuf:"bytes,3,opt,name=original_title,json=originalTitle,proto3" json:"original_title,omitempty"`
+	Tagline       string                 `protobuf:"bytes,4,opt,name=tagline,proto3" json:"tagline,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	// minutes
+	Duration int32 `protobuf:"varint,6,opt,name=duration,proto3" json:"duration,omitempty"`
+	// YYYY-MM-DD
+	ReleaseDate string `protobuf:"bytes,7,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	// ISO 639-1
+	OriginalLanguage string `protobuf:"bytes,8,opt,name=original_language,json=originalLanguage,proto3" json:"original_language,omitempty"`
+	// ISO 3166-1 alpha-2
+	ProductionCountries []string  `protobuf:"bytes,9,rep,name=production_countries,json=productionCountries,proto3" json:"production_countries,omitempty"`
+	AgeCertification    string    `protobuf:"bytes,10,opt,name=age_certification,json=ageCertification,proto3" json:"age_certification,omitempty"`
+	Credits             []*Credit `protobuf:"bytes,11,rep,name=credits,proto3" json:"credits,omitempty"`
+	GenreIds            []int32   `protobuf:"varint,12,rep,packed,name=genre_ids,json=genreIds,proto3" json:"genre_ids,omitempty"`
+	// language the title, description and tagline are in
+	Language      string `protobuf:"bytes,13,opt,name=language,proto3" json:"language,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Movie) Reset() {
+	*x = Movie{}
+	mi := &file_movie_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Movie) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Movie) ProtoMessage() {}
+
+func (x *Movie) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Movie.ProtoReflect.Descriptor instead.
+func (*Movie) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Movie) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Movie) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Movie) GetOriginalTitle() string {
+	if x != nil {
+		return x.OriginalTitle
+	}
+	return ""
+}
+
+func (x *Movie) GetTagline() string {
+	if x != nil {
+		return x.Tagline
+	}
+	return ""
+}
+
+func (x *Movie) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Movie) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *Movie) GetReleaseDate() string {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return ""
+}
+
+func (x *Movie) GetOriginalLanguage() string {
+	if x != nil {
+		return x.OriginalLanguage
+	}
+	return ""
+}
+
+func (x *Movie) GetProductionCountries() []string {
+	if x != nil {
+		return x.ProductionCountries
+	}
+	return nil
+}
+
+func (x *Movie) GetAgeCertification() string {
+	if x != nil {
+		return x.AgeCertification
+	}
+	return ""
+}
+
+func (x *Movie) GetCredits() []*Credit {
+	if x != nil {
+		return x.Credits
+	}
+	return nil
+}
+
+func (x *Movie) GetGenreIds() []int32 {
+	if x != nil {
+		return x.GenreIds
+	}
+	return nil
+}
+
+func (x *Movie) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+type Credit struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ArtistId  int32                  `protobuf:"varint,1,opt,name=artist_id,json=artistId,proto3" json:"artist_id,omitempty"`
+	Role      Role                   `protobuf:"varint,2,opt,name=role,proto3,enum=movies.v1.Role" json:"role,omitempty"`
+	Character string                 `protobuf:"bytes,3,opt,name=character,proto3" json:"character,omitempty"`
+	// billing order
+	Order         int32 `protobuf:"varint,4,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Credit) Reset() {
+	*x = Credit{}
+	mi := &file_movie_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Credit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Credit) ProtoMessage() {}
+
+func (x *Credit) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Credit.ProtoReflect.Descriptor instead.
+func (*Credit) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Credit) GetArtistId() int32 {
+	if x != nil {
+		return x.ArtistId
+	}
+	return 0
+}
+
+func (x *Credit) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+func (x *Credit) GetCharacter() string {
+	if x != nil {
+		return x.Character
+	}
+	return ""
+}
+
+func (x *Credit) GetOrder() int32 {
+	if x != nil {
+		return x.Order
+	}
+	return 0
+}
+
+// an existing artist or genre by ID or by name
+type Reference struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Ref:
+	//
+	//	*Reference_Id
+	//	*Reference_Name
+	Ref           isReference_Ref `protobuf_oneof:"ref"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reference) Reset() {
+	*x = Reference{}
+	mi := &file_movie_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reference) ProtoMessage() {}
+
+func (x *Reference) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reference.ProtoReflect.Descriptor instead.
+func (*Reference) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Reference) GetRef() isReference_Ref {
+	if x != nil {
+		return x.Ref
+	}
+	return nil
+}
+
+func (x *Reference) GetId() int32 {
+	if x != nil {
+		if x, ok := x.Ref.(*Reference_Id); ok {
+			return x.Id
+		}
+	}
+	return 0
+}
+
+func (x *Reference) GetName() string {
+	if x != nil {
+		if x, ok := x.Ref.(*Reference_Name); ok {
+			return x.Name
+		}
+	}
+	return ""
+}
+
+type isReference_Ref interface {
+	isReference_Ref()
+}
+
+type Reference_Id struct {
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
+}
+
+type Reference_Name struct {
+	Name string `protobuf:"bytes,2,opt,name=name,proto3,oneof"`
+}
+
+func (*Reference_Id) isReference_Ref() {}
+
+func (*Reference_Name) isReference_Ref() {}
+
+// same as the body of POST /movies, artists listed without credits are
+// credited as actors in the given order
+type MovieInput struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title               string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	OriginalTitle       string                 `protobuf:"bytes,3,opt,name=original_title,json=originalTitle,proto3" json:"original_title,omitempty"`
+	Tagline             string                 `protobuf:"bytes,4,opt,name=tagline,proto3" json:"tagline,omitempty"`
+	Description         string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Duration            int32                  `protobuf:"varint,6,opt,name=duration,proto3" json:"duration,omitempty"`
+	ReleaseDate         string                 `protobuf:"bytes,7,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	OriginalLanguage    string                 `protobuf:"bytes,8,opt,name=original_language,json=originalLanguage,proto3" json:"original_language,omitempty"`
+	ProductionCountries []string               `protobuf:"bytes,9,rep,name=production_countries,json=productionCountries,proto3" json:"production_countries,omitempty"`
+	AgeCertification    string                 `protobuf:"bytes,10,opt,name=age_certification,json=ageCertification,proto3" json:"age_certification,omitempty"`
+	Credits             []*CreditInput         `protobuf:"bytes,11,rep,name=credits,proto3" json:"credits,omitempty"`
+	Artists             []*Reference           `protobuf:"bytes,12,rep,name=artists,proto3" json:"artists,omitempty"`
+	Genres              []*Reference           `protobuf:"bytes,13,rep,name=genres,proto3" json:"genres,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *MovieInput) Reset() {
+	*x = MovieInput{}
+	mi := &file_movie_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MovieInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MovieInput) ProtoMessage() {}
+
+func (x *MovieInput) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MovieInput.ProtoReflect.Descriptor instead.
+func (*MovieInput) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MovieInput) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MovieInput) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MovieInput) GetOriginalTitle() string {
+	if x != nil {
+		return x.OriginalTitle
+	}
+	return ""
+}
+
+func (x *MovieInput) GetTagline() string {
+	if x != nil {
+		return x.Tagline
+	}
+	return ""
+}
+
+func (x *MovieInput) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *MovieInput) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *MovieInput) GetReleaseDate() string {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return ""
+}
+
+func (x *MovieInput) GetOriginalLanguage() string {
+	if x != nil {
+		return x.OriginalLanguage
+	}
+	return ""
+}
+
+func (x *MovieInput) GetProductionCountries() []string {
+	if x != nil {
+		return x.ProductionCountries
+	}
+	return nil
+}
+
+func (x *MovieInput) GetAgeCertification() string {
+	if x != nil {
+		return x.AgeCertification
+	}
+	return ""
+}
+
+func (x *MovieInput) GetCredits() []*CreditInput {
+	if x != nil {
+		return x.Credits
+	}
+	return nil
+}
+
+func (x *MovieInput) GetArtists() []*Reference {
+	if x != nil {
+		return x.Artists
+	}
+	return nil
+}
+
+func (x *MovieInput) GetGenres() []*Reference {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+type CreditInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Artist        *Reference             `protobuf:"bytes,1,opt,name=artist,proto3" json:"artist,omitempty"`
+	Role          Role                   `protobuf:"varint,2,opt,name=role,proto3,enum=movies.v1.Role" json:"role,omitempty"`
+	Character     string                 `protobuf:"bytes,3,opt,name=character,proto3" json:"character,omitempty"`
+	Order         int32                  `protobuf:"varint,4,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreditInput) Reset() {
+	*x = CreditInput{}
+	mi := &file_movie_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreditInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreditInput) ProtoMessage() {}
+
+func (x *CreditInput) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreditInput.ProtoReflect.Descriptor instead.
+func (*CreditInput) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreditInput) GetArtist() *Reference {
+	if x != nil {
+		return x.Artist
+	}
+	return nil
+}
+
+func (x *CreditInput) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+func (x *CreditInput) GetCharacter() string {
+	if x != nil {
+		return x.Character
+	}
+	return ""
+}
+
+func (x *CreditInput) GetOrder() int32 {
+	if x != nil {
+		return x.Order
+	}
+	return 0
+}
+
+type GetMovieRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// language to read the movie in, the original language when empty or
+	// untranslated
+	Lang          string `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMovieRequest) Reset() {
+	*x = GetMovieRequest{}
+	mi := &file_movie_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMovieRequest) ProtoMessage() {}
+
+func (x *GetMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMovieRequest.ProtoReflect.Descriptor instead.
+func (*GetMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetMovieRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetMovieRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type ListMoviesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// defaults to 1
+	Page int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// defaults to the server's default limit
+	Limit         int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Lang          string `protobuf:"bytes,3,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMoviesRequest) Reset() {
+	*x = ListMoviesRequest{}
+	mi := &file_movie_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMoviesRequest) ProtoMessage() {}
+
+func (x *ListMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMoviesRequest.ProtoReflect.Descriptor instead.
+func (*ListMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListMoviesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListMoviesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListMoviesRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type SearchMoviesRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Artist      string                 `protobuf:"bytes,3,opt,name=artist,proto3" json:"artist,omitempty"`
+	Character   string                 `protobuf:"bytes,4,opt,name=character,proto3" json:"character,omitempty"`
+	// only match artists and characters credited with this role
+	Role          Role   `protobuf:"varint,5,opt,name=role,proto3,enum=movies.v1.Role" json:"role,omitempty"`
+	Genre         string `protobuf:"bytes,6,opt,name=genre,proto3" json:"genre,omitempty"`
+	YearFrom      int32  `protobuf:"varint,7,opt,name=year_from,json=yearFrom,proto3" json:"year_from,omitempty"`
+	YearTo        int32  `protobuf:"varint,8,opt,name=year_to,json=yearTo,proto3" json:"year_to,omitempty"`
+	Language      string `protobuf:"bytes,9,opt,name=language,proto3" json:"language,omitempty"`
+	Country       string `protobuf:"bytes,10,opt,name=country,proto3" json:"country,omitempty"`
+	Certification string `protobuf:"bytes,11,opt,name=certification,proto3" json:"certification,omitempty"`
+	Page          int32  `protobuf:"varint,12,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32  `protobuf:"varint,13,opt,name=limit,proto3" json:"limit,omitempty"`
+	Lang          string `protobuf:"bytes,14,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMoviesRequest) Reset() {
+	*x = SearchMoviesRequest{}
+	mi := &file_movie_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMoviesRequest) ProtoMessage() {}
+
+func (x *SearchMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMoviesRequest.ProtoReflect.Descriptor instead.
+func (*SearchMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchMoviesRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetArtist() string {
+	if x != nil {
+		return x.Artist
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetCharacter() string {
+	if x != nil {
+		return x.Character
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+func (x *SearchMoviesRequest) GetGenre() string {
+	if x != nil {
+		return x.Genre
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetYearFrom() int32 {
+	if x != nil {
+		return x.YearFrom
+	}
+	return 0
+}
+
+func (x *SearchMoviesRequest) GetYearTo() int32 {
+	if x != nil {
+		return x.YearTo
+	}
+	return 0
+}
+
+func (x *SearchMoviesRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetCertification() string {
+	if x != nil {
+		return x.Certification
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *SearchMoviesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchMoviesRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type MoviePage struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Movies []*Movie               `protobuf:"bytes,1,rep,name=movies,proto3" json:"movies,omitempty"`
+	Page   int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit  int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// number of movies across every page
+	Count         int32 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoviePage) Reset() {
+	*x = MoviePage{}
+	mi := &file_movie_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoviePage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoviePage) ProtoMessage() {}
+
+func (x *MoviePage) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoviePage.ProtoReflect.Descriptor instead.
+func (*MoviePage) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MoviePage) GetMovies() []*Movie {
+	if x != nil {
+		return x.Movies
+	}
+	return nil
+}
+
+func (x *MoviePage) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *MoviePage) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *MoviePage) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type CreateMovieRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Movie         *MovieInput            `protobuf:"bytes,1,opt,name=movie,proto3" json:"movie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateMovieRequest) Reset() {
+	*x = CreateMovieRequest{}
+	mi := &file_movie_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateMovieRequest) ProtoMessage() {}
+
+func (x *CreateMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateMovieRequest.ProtoReflect.Descriptor instead.
+func (*CreateMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateMovieRequest) GetMovie() *MovieInput {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+type UpdateMovieRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Movie         *MovieInput            `protobuf:"bytes,2,opt,name=movie,proto3" json:"movie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMovieRequest) Reset() {
+	*x = UpdateMovieRequest{}
+	mi := &file_movie_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMovieRequest) ProtoMessage() {}
+
+func (x *UpdateMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMovieRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateMovieRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateMovieRequest) GetMovie() *MovieInput {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+type DeleteMovieRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMovieRequest) Reset() {
+	*x = DeleteMovieRequest{}
+	mi := &file_movie_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMovieRequest) ProtoMessage() {}
+
+func (x *DeleteMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMovieRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteMovieRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteMovieResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMovieResponse) Reset() {
+	*x = DeleteMovieResponse{}
+	mi := &file_movie_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMovieResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMovieResponse) ProtoMessage() {}
+
+func (x *DeleteMovieResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMovieResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMovieResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{12}
+}
+
+type WatchMoviesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// only events about these movies
+	MovieIds []int32 `protobuf:"varint,1,rep,packed,name=movie_ids,json=movieIds,proto3" json:"movie_ids,omitempty"`
+	// only events about movies in these genres
+	GenreIds []int32 `protobuf:"varint,2,rep,packed,name=genre_ids,json=genreIds,proto3" json:"genre_ids,omitempty"`
+	// resume after this sequence number, like Last-Event-ID
+	AfterSeq      uint64 `protobuf:"varint,3,opt,name=after_seq,json=afterSeq,proto3" json:"after_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchMoviesRequest) Reset() {
+	*x = WatchMoviesRequest{}
+	mi := &file_movie_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchMoviesRequest) ProtoMessage() {}
+
+func (x *WatchMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchMoviesRequest.ProtoReflect.Descriptor instead.
+func (*WatchMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchMoviesRequest) GetMovieIds() []int32 {
+	if x != nil {
+		return x.MovieIds
+	}
+	return nil
+}
+
+func (x *WatchMoviesRequest) GetGenreIds() []int32 {
+	if x != nil {
+		return x.GenreIds
+	}
+	return nil
+}
+
+func (x *WatchMoviesRequest) GetAfterSeq() uint64 {
+	if x != nil {
+		return x.AfterSeq
+	}
+	return 0
+}
+
+type MovieEvent struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Seq        uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Id         string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Type       MovieEvent_Type        `protobuf:"varint,3,opt,name=type,proto3,enum=movies.v1.MovieEvent_Type" json:"type,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	MovieId    int32                  `protobuf:"varint,5,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	// after the change, or as it was before a deletion
+	Movie         *Movie `protobuf:"bytes,6,opt,name=movie,proto3" json:"movie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MovieEvent) Reset() {
+	*x = MovieEvent{}
+	mi := &file_movie_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MovieEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MovieEvent) ProtoMessage() {}
+
+func (x *MovieEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MovieEvent.ProtoReflect.Descriptor instead.
+func (*MovieEvent) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MovieEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *MovieEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MovieEvent) GetType() MovieEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return MovieEvent_TYPE_UNSPECIFIED
+}
+
+func (x *MovieEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *MovieEvent) GetMovieId() int32 {
+	if x != nil {
+		return x.MovieId
+	}
+	return 0
+}
+
+func (x *MovieEvent) GetMovie() *Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+var File_movie_proto protoreflect.FileDescriptor
+
+const file_movie_proto_rawDesc = "" +
+	"\n" +
+	"\vmovie.proto\x12\tmovies.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc2\x03\n" +
+	"\x05Movie\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12%\n" +
+	"\x0eoriginal_title\x18\x03 \x01(\tR\roriginalTitle\x12\x18\n" +
+	"\atagline\x18\x04 \x01(\tR\atagline\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bduration\x18\x06 \x01(\x05R\bduration\x12!\n" +
+	"\frelease_date\x18\a \x01(\tR\vreleaseDate\x12+\n" +
+	"\x11original_language\x18\b \x01(\tR\x10originalLanguage\x121\n" +
+	"\x14production_countries\x18\t \x03(\tR\x13productionCountries\x12+\n" +
+	"\x11age_certification\x18\n" +
+	" \x01(\tR\x10ageCertification\x12+\n" +
+	"\acredits\x18\v \x03(\v2\x11.movies.v1.CreditR\acredits\x12\x1b\n" +
+	"\tgenre_ids\x18\f \x03(\x05R\bgenreIds\x12\x1a\n" +
+	"\blanguage\x18\r \x01(\tR\blanguage\"~\n" +
+	"\x06Credit\x12\x1b\n" +
+	"\tartist_id\x18\x01 \x01(\x05R\bartistId\x12#\n" +
+	"\x04role\x18\x02 \x01(\x0e2\x0f.movies.v1.RoleR\x04role\x12\x1c\n" +
+	"\tcharacter\x18\x03 \x01(\tR\tcharacter\x12\x14\n" +
+	"\x05order\x18\x04 \x01(\x05R\x05order\":\n" +
+	"\tReference\x12\x10\n" +
+	"\x02id\x18\x01 \x01(\x05H\x00R\x02id\x12\x14\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04nameB\x05\n" +
+	"\x03ref\"\xf1\x03\n" +
+	"\n" +
+	"MovieInput\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12%\n" +
+	"\x0eoriginal_title\x18\x03 \x01(\tR\roriginalTitle\x12\x18\n" +
+	"\atagline\x18\x04 \x01(\tR\atagline\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bduration\x18\x06 \x01(\x05R\bduration\x12!\n" +
+	"\frelease_date\x18\a \x01(\tR\vreleaseDate\x12+\n" +
+	"\x11original_language\x18\b \x01(\tR\x10originalLanguage\x121\n" +
+	"\x14production_countries\x18\t \x03(\tR\x13productionCountries\x12+\n" +
+	"\x11age_certification\x18\n" +
+	" \x01(\tR\x10ageCertification\x120\n" +
+	"\acredits\x18\v \x03(\v2\x16.movies.v1.CreditInputR\acredits\x12.\n" +
+	"\aartists\x18\f \x03(\v2\x14.movies.v1.ReferenceR\aartists\x12,\n" +
+	"\x06genres\x18\r \x03(\v2\x14.movies.v1.ReferenceR\x06genres\"\x94\x01\n" +
+	"\vCreditInput\x12,\n" +
+	"\x06artist\x18\x01 \x01(\v2\x14.movies.v1.ReferenceR\x06artist\x12#\n" +
+	"\x04role\x18\x02 \x01(\x0e2\x0f.movies.v1.RoleR\x04role\x12\x1c\n" +
+	"\tcharacter\x18\x03 \x01(\tR\tcharacter\x12\x14\n" +
+	"\x05order\x18\x04 \x01(\x05R\x05order\"5\n" +
+	"\x0fGetMovieRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04lang\x18\x02 \x01(\tR\x04lang\"Q\n" +
+	"\x11ListMoviesRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04lang\x18\x03 \x01(\tR\x04lang\"\x8e\x03\n" +
+	"\x13SearchMoviesRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06artist\x18\x03 \x01(\tR\x06artist\x12\x1c\n" +
+	"\tcharacter\x18\x04 \x01(\tR\tcharacter\x12#\n" +
+	"\x04role\x18\x05 \x01(\x0e2\x0f.movies.v1.RoleR\x04role\x12\x14\n" +
+	"\x05genre\x18\x06 \x01(\tR\x05genre\x12\x1b\n" +
+	"\tyear_from\x18\a \x01(\x05R\byearFrom\x12\x17\n" +
+	"\ayear_to\x18\b \x01(\x05R\x06yearTo\x12\x1a\n" +
+	"\blanguage\x18\t \x01(\tR\blanguage\x12\x18\n" +
+	"\acountry\x18\n" +
+	" \x01(\tR\acountry\x12$\n" +
+	"\rcertification\x18\v \x01(\tR\rcertification\x12\x12\n" +
+	"\x04page\x18\f \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\r \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04lang\x18\x0e \x01(\tR\x04lang\"u\n" +
+	"\tMoviePage\x12(\n" +
+	"\x06movies\x18\x01 \x03(\v2\x10.movies.v1.MovieR\x06movies\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\"A\n" +
+	"\x12CreateMovieRequest\x12+\n" +
+	"\x05movie\x18\x01 \x01(\v2\x15.movies.v1.MovieInputR\x05movie\"Q\n" +
+	"\x12UpdateMovieRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12+\n" +
+	"\x05movie\x18\x02 \x01(\v2\x15.movies.v1.MovieInputR\x05movie\"$\n" +
+	"\x12DeleteMovieRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x15\n" +
+	"\x13DeleteMovieResponse\"k\n" +
+	"\x12WatchMoviesRequest\x12\x1b\n" +
+	"\tmovie_ids\x18\x01 \x03(\x05R\bmovieIds\x12\x1b\n" +
+	"\tgenre_ids\x18\x02 \x03(\x05R\bgenreIds\x12\x1b\n" +
+	"\tafter_seq\x18\x03 \x01(\x04R\bafterSeq\"\xc3\x02\n" +
+	"\n" +
+	"MovieEvent\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12.\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x1a.movies.v1.MovieEvent.TypeR\x04type\x12;\n" +
+	"\voccurred_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x19\n" +
+	"\bmovie_id\x18\x05 \x01(\x05R\amovieId\x12&\n" +
+	"\x05movie\x18\x06 \x01(\v2\x10.movies.v1.MovieR\x05movie\"c\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fTYPE_CREATED\x10\x01\x12\x10\n" +
+	"\fTYPE_UPDATED\x10\x02\x12\x10\n" +
+	"\fTYPE_DELETED\x10\x03\x12\x0f\n" +
+	"\vTYPE_RESYNC\x10\x04*c\n" +
+	"\x04Role\x12\x14\n" +
+	"\x10ROLE_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"ROLE_ACTOR\x10\x01\x12\x11\n" +
+	"\rROLE_DIRECTOR\x10\x02\x12\x0f\n" +
+	"\vROLE_WRITER\x10\x03\x12\x11\n" +
+	"\rROLE_COMPOSER\x10\x042\xbf\x03\n" +
+	"\fMovieService\x123\n" +
+	"\x03Get\x12\x1a.movies.v1.GetMovieRequest\x1a\x10.movies.v1.Movie\x12:\n" +
+	"\x04List\x12\x1c.movies.v1.ListMoviesRequest\x1a\x14.movies.v1.MoviePage\x12>\n" +
+	"\x06Search\x12\x1e.movies.v1.SearchMoviesRequest\x1a\x14.movies.v1.MoviePage\x129\n" +
+	"\x06Create\x12\x1d.movies.v1.CreateMovieRequest\x1a\x10.movies.v1.Movie\x129\n" +
+	"\x06Update\x12\x1d.movies.v1.UpdateMovieRequest\x1a\x10.movies.v1.Movie\x12G\n" +
+	"\x06Delete\x12\x1d.movies.v1.DeleteMovieRequest\x1a\x1e.movies.v1.DeleteMovieResponse\x12?\n" +
+	"\x05Watch\x12\x1d.movies.v1.WatchMoviesRequest\x1a\x15.movies.v1.MovieEvent0\x01B1Z/github.com/sglkc/roketin-be-test/chal-2/moviepbb\x06proto3"
+
+var (
+	file_movie_proto_rawDescOnce sync.Once
+	file_movie_proto_rawDescData []byte
+)
+
+func file_movie_proto_rawDescGZIP() []byte {
+	file_movie_proto_rawDescOnce.Do(func() {
+		file_movie_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_movie_proto_rawDesc), len(file_movie_proto_rawDesc)))
+	})
+	return file_movie_proto_rawDescData
+}
+
+var file_movie_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_movie_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_movie_proto_goTypes = []any{
+	(Role)(0),                     // 0: movies.v1.Role
+	(MovieEvent_Type)(0),          // 1: movies.v1.MovieEvent.Type
+	(*Movie)(nil),                 // 2: movies.v1.Movie
+	(*Credit)(nil),                // 3: movies.v1.Credit
+	(*Reference)(nil),             // 4: movies.v1.Reference
+	(*MovieInput)(nil),            // 5: movies.v1.MovieInput
+	(*CreditInput)(nil),           // 6: movies.v1.CreditInput
+	(*GetMovieRequest)(nil),       // 7: movies.v1.GetMovieRequest
+	(*ListMoviesRequest)(nil),     // 8: movies.v1.ListMoviesRequest
+	(*SearchMoviesRequest)(nil),   // 9: movies.v1.SearchMoviesRequest
+	(*MoviePage)(nil),             // 10: movies.v1.MoviePage
+	(*CreateMovieRequest)(nil),    // 11: movies.v1.CreateMovieRequest
+	(*UpdateMovieRequest)(nil),    // 12: movies.v1.UpdateMovieRequest
+	(*DeleteMovieRequest)(nil),    // 13: movies.v1.DeleteMovieRequest
+	(*DeleteMovieResponse)(nil),   // 14: movies.v1.DeleteMovieResponse
+	(*WatchMoviesRequest)(nil),    // 15: movies.v1.WatchMoviesRequest
+	(*MovieEvent)(nil),            // 16: movies.v1.MovieEvent
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+}
+var file_movie_proto_depIdxs = []int32{
+	3,  // 0: movies.v1.Movie.credits:type_name -> movies.v1.Credit
+	0,  // 1: movies.v1.Credit.role:type_name -> movies.v1.Role
+	6,  // 2: movies.v1.MovieInput.credits:type_name -> movies.v1.CreditInput
+	4,  // 3: movies.v1.MovieInput.artists:type_name -> movies.v1.Reference
+	4,  // 4: movies.v1.MovieInput.genres:type_name -> movies.v1.Reference
+	4,  // 5: movies.v1.CreditInput.artist:type_name -> movies.v1.Reference
+	0,  // 6: movies.v1.CreditInput.role:type_name -> movies.v1.Role
+	0,  // 7: movies.v1.SearchMoviesRequest.role:type_name -> movies.v1.Role
+	2,  // 8: movies.v1.MoviePage.movies:type_name -> movies.v1.Movie
+	5,  // 9: movies.v1.CreateMovieRequest.movie:type_name -> movies.v1.MovieInput
+	5,  // 10: movies.v1.UpdateMovieRequest.movie:type_name -> movies.v1.MovieInput
+	1,  // 11: movies.v1.MovieEvent.type:type_name -> movies.v1.MovieEvent.Type
+	17, // 12: movies.v1.MovieEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	2,  // 13: movies.v1.MovieEvent.movie:type_name -> movies.v1.Movie
+	7,  // 14: movies.v1.MovieService.Get:input_type -> movies.v1.GetMovieRequest
+	8,  // 15: movies.v1.MovieService.List:input_type -> movies.v1.ListMoviesRequest
+	9,  // 16: movies.v1.MovieService.Search:input_type -> movies.v1.SearchMoviesRequest
+	11, // 17: movies.v1.MovieService.Create:input_type -> movies.v1.CreateMovieRequest
+	12, // 18: movies.v1.MovieService.Update:input_type -> movies.v1.UpdateMovieRequest
+	13, // 19: movies.v1.MovieService.Delete:input_type -> movies.v1.DeleteMovieRequest
+	15, // 20: movies.v1.MovieService.Watch:input_type -> movies.v1.WatchMoviesRequest
+	2,  // 21: movies.v1.MovieService.Get:output_type -> movies.v1.Movie
+	10, // 22: movies.v1.MovieService.List:output_type -> movies.v1.MoviePage
+	10, // 23: movies.v1.MovieService.Search:output_type -> movies.v1.MoviePage
+	2,  // 24: movies.v1.MovieService.Create:output_type -> movies.v1.Movie
+	2,  // 25: movies.v1.MovieService.Update:output_type -> movies.v1.Movie
+	14, // 26: movies.v1.MovieService.Delete:output_type -> movies.v1.DeleteMovieResponse
+	16, // 27: movies.v1.MovieService.Watch:output_type -> movies.v1.MovieEvent
+	21, // [21:28] is the sub-list for method output_type
+	14, // [14:21] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_movie_proto_init() }
+func file_movie_proto_init() {
+	if File_movie_proto != nil {
+		return
+	}
+	file_movie_proto_msgTypes[2].OneofWrappers = []any{
+		(*Reference_Id)(nil),
+		(*Reference_Name)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_movie_proto_rawDesc), len(file_movie_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_movie_proto_goTypes,
+		DependencyIndexes: file_movie_proto_depIdxs,
+		EnumInfos:         file_movie_proto_enumTypes,
+		MessageInfos:      file_movie_proto_msgTypes,
+	}.Build()
+	File_movie_proto = out.File
+	file_movie_proto_goTypes = nil
+	file_movie_proto_depIdxs = nil
+}
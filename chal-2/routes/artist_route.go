@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/controllers"
+)
+
+func RegisterArtistRoutes(router gin.IRouter) {
+	router.GET("/artists", controllers.GetArtists)
+	router.GET("/artists/:id", controllers.GetArtistById)
+	router.POST("/artists", controllers.PostArtist)
+	router.PUT("/artists/:id", controllers.UpdateArtist)
+	router.DELETE("/artists/:id", controllers.DeleteArtist)
+	router.GET("/artists/:id/movies", controllers.GetArtistMovies)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/controllers"
+	"github.com/sglkc/roketin-be-test/chal-2/middlewares"
+)
+
+// the audit log names who changed what, only authenticated actors may read it
+func RegisterAuditRoutes(router gin.IRouter) {
+	router.GET("/audit", middlewares.RequireActor(), controllers.GetAuditEntries)
+}
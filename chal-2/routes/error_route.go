@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+)
+
+// unmatched requests get a problem body too, instead of gin's plain text
+func RegisterErrorRoutes(router *gin.Engine) {
+	router.HandleMethodNotAllowed = true
+
+	router.NoRoute(func(c *gin.Context) {
+		utils.Problem(c, dto.CodeRouteNotFound, "No route for %s %s", c.Request.Method, c.Request.URL.Path)
+	})
+
+	router.NoMethod(func(c *gin.Context) {
+		utils.Problem(c, dto.CodeMethodNotAllowed, "%s is not allowed on %s", c.Request.Method, c.Request.URL.Path)
+	})
+}
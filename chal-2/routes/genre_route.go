@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/controllers"
+)
+
+func RegisterGenreRoutes(router gin.IRouter) {
+	router.GET("/genres", controllers.GetGenres)
+	router.GET("/genres/:id", controllers.GetGenreById)
+	router.POST("/genres", controllers.PostGenre)
+	router.PUT("/genres/:id", controllers.UpdateGenre)
+	router.DELETE("/genres/:id", controllers.DeleteGenre)
+	router.GET("/genres/:id/movies", controllers.GetGenreMovies)
+}
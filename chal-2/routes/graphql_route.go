@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/controllers"
+)
+
+func RegisterGraphqlRoutes(router *gin.Engine) {
+	router.POST("/graphql", controllers.PostGraphql)
+
+	// the playground is a development tool, gin_mode debug is dev mode
+	if gin.IsDebugging() {
+		router.GET("/graphql", controllers.GetGraphiql)
+	}
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/controllers"
+)
+
+func RegisterHealthRoutes(router *gin.Engine) {
+	router.GET("/healthz", controllers.Healthz)
+	router.GET("/readyz", controllers.Readyz)
+	router.GET("/version", controllers.Version)
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sglkc/roketin-be-test/chal-2/middlewares"
+)
+
+// must be registered before other routes so the middleware applies to them
+func RegisterMetricsRoutes(router *gin.Engine) {
+	router.Use(middlewares.Metrics())
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+}
@@ -0,0 +1,90 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+)
+
+func TestMetricsRoute(t *testing.T) {
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	RegisterMetricsRoutes(router)
+	RegisterMovieRoutes(router)
+
+	for _, path := range []string{"/movies?limit=5", "/movies/search?title=final", "/movies/999"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(w.Body)
+	if err != nil {
+		t.Fatalf("failed to parse metrics: %v", err)
+	}
+
+	for _, name := range []string{
+		"movies_api_http_requests_total",
+		"movies_api_http_request_duration_seconds",
+		"movies_api_movies_stored",
+		"movies_api_search_results",
+		"movies_api_pagination_limit",
+		"go_goroutines",
+	} {
+		if _, ok := families[name]; !ok {
+			t.Errorf("expected metric %s to be exposed", name)
+		}
+	}
+
+	requests := families["movies_api_http_requests_total"]
+	if requests == nil {
+		t.FailNow()
+	}
+
+	if !hasLabels(requests, map[string]string{"route": "/movies/:id", "status": "404"}) {
+		t.Errorf("expected request counter labelled by route template and status")
+	}
+
+	if stored := families["movies_api_movies_stored"].GetMetric()[0].GetGauge().GetValue(); stored < 1 {
+		t.Errorf("expected stored movies gauge to be positive, got %v", stored)
+	}
+
+	if count := families["movies_api_search_results"].GetMetric()[0].GetHistogram().GetSampleCount(); count < 1 {
+		t.Errorf("expected at least one search results sample, got %d", count)
+	}
+}
+
+func hasLabels(family *dto.MetricFamily, labels map[string]string) bool {
+	for _, metric := range family.GetMetric() {
+		matched := 0
+
+		for _, pair := range metric.GetLabel() {
+			if value, ok := labels[pair.GetName()]; ok && value == pair.GetValue() {
+				matched++
+			}
+		}
+
+		if matched == len(labels) {
+			return true
+		}
+	}
+
+	return false
+}
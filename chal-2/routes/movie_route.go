@@ -5,11 +5,21 @@ import (
 	"github.com/sglkc/roketin-be-test/chal-2/controllers"
 )
 
-func RegisterMovieRoutes(router *gin.Engine) {
+func RegisterMovieRoutes(router gin.IRouter) {
 	router.GET("/movies", controllers.GetMovies)
 	router.GET("/movies/:id", controllers.GetMovieById)
 	router.GET("/movies/search", controllers.SearchMovie)
+	router.GET("/movies/events", controllers.GetMovieEvents)
 	router.POST("/movies", controllers.PostMovie)
+	router.POST("/movies/batch", controllers.PostMovieBatch)
 	router.PUT("/movies/:id", controllers.UpdateMovie)
 	router.DELETE("/movies/:id", controllers.DeleteMovie)
+
+	router.GET("/movies/:id/revisions", controllers.GetMovieRevisions)
+	router.GET("/movies/:id/revisions/:rev/diff", controllers.GetMovieRevisionDiff)
+	router.POST("/movies/:id/revisions/:rev/restore", controllers.RestoreMovieRevision)
+
+	router.GET("/movies/:id/translations", controllers.GetMovieTranslations)
+	router.PUT("/movies/:id/translations/:lang", controllers.PutMovieTranslation)
+	router.DELETE("/movies/:id/translations/:lang", controllers.DeleteMovieTranslation)
 }
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/middlewares"
+)
+
+// the resource routes of v1, served under /v1. A breaking change such as a
+// new error format goes in a v2 group with its own routes and middlewares,
+// so clients of v1 keep the shape they were written against.
+func RegisterV1Routes(router gin.IRouter) {
+	RegisterMovieRoutes(router)
+	RegisterArtistRoutes(router)
+	RegisterGenreRoutes(router)
+	RegisterAuditRoutes(router)
+	RegisterWebhookRoutes(router)
+}
+
+// the routes as they were before versioning, aliases of v1 telling clients
+// to move to /v1 before the sunset
+func RegisterUnversionedRoutes(router *gin.Engine, deprecation, sunset time.Time) {
+	RegisterV1Routes(router.Group("", middlewares.Deprecated(deprecation, sunset, "/v1")))
+}
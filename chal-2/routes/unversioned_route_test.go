@@ -0,0 +1,103 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/config"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+)
+
+func TestUnversionedRoutesAreDeprecated(t *testing.T) {
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	deprecation := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+
+	router := gin.New()
+	RegisterV1Routes(router.Group("/v1"))
+	RegisterUnversionedRoutes(router, deprecation, sunset)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	versioned := serve("/v1/movies/1?lang=en")
+	if versioned.Code != http.StatusOK || versioned.Header().Get("Deprecation") != "" {
+		t.Errorf("expected /v1 to be served without deprecation, got %d %v", versioned.Code, versioned.Header())
+	}
+
+	unversioned := serve("/movies/1?lang=en")
+	if unversioned.Code != http.StatusOK || unversioned.Body.String() != versioned.Body.String() {
+		t.Errorf("expected /movies/1 to be an alias of /v1/movies/1, got %d", unversioned.Code)
+	}
+
+	for header, want := range map[string]string{
+		"Deprecation": "@1792368000",
+		"Sunset":      "Mon, 19 Apr 2027 00:00:00 GMT",
+		"Link":        `</v1/movies/1?lang=en>; rel="successor-version"`,
+	} {
+		if got := unversioned.Header().Get(header); got != want {
+			t.Errorf("expected %s header %q, got %q", header, want, got)
+		}
+	}
+}
+
+// nothing has to be configured for clients to be told to move to /v1
+func TestUnversionedRoutesAreDeprecatedByDefault(t *testing.T) {
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+
+	router := gin.New()
+	RegisterUnversionedRoutes(router, cfg.UnversionedRoutes.Deprecation, cfg.UnversionedRoutes.Sunset)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/1", nil))
+
+	if got := w.Header().Get("Deprecation"); got != "@1792368000" {
+		t.Errorf("expected the routes to be deprecated since the release of /v1, got %q", got)
+	}
+
+	if got := w.Header().Get("Sunset"); got != "" {
+		t.Errorf("expected no sunset by default, got %q", got)
+	}
+}
+
+func TestUnversionedRoutesWithoutDeprecation(t *testing.T) {
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	RegisterUnversionedRoutes(router, time.Time{}, time.Time{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/1", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected /movies/1 to be served, got %d", w.Code)
+	}
+
+	for _, header := range []string{"Deprecation", "Sunset", "Link"} {
+		if got := w.Header().Get(header); got != "" {
+			t.Errorf("expected no %s header without a deprecation date, got %q", header, got)
+		}
+	}
+}
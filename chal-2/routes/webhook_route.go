@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/controllers"
+	"github.com/sglkc/roketin-be-test/chal-2/middlewares"
+)
+
+// webhooks send catalogue changes to any URL, only authenticated actors may
+// manage them
+func RegisterWebhookRoutes(router gin.IRouter) {
+	router = router.Group("", middlewares.RequireActor())
+
+	router.GET("/webhooks", controllers.GetWebhooks)
+	router.GET("/webhooks/dead-letters", controllers.GetDeadLetters)
+	router.POST("/webhooks/dead-letters/:id/redeliver", controllers.RedeliverDeadLetter)
+	router.GET("/webhooks/:id", controllers.GetWebhookById)
+	router.POST("/webhooks", controllers.PostWebhook)
+	router.PUT("/webhooks/:id", controllers.UpdateWebhook)
+	router.DELETE("/webhooks/:id", controllers.DeleteWebhook)
+	router.GET("/webhooks/:id/deliveries", controllers.GetWebhookDeliveries)
+}
@@ -0,0 +1,459 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/feed"
+	"github.com/sglkc/roketin-be-test/chal-2/i18n"
+	"github.com/sglkc/roketin-be-test/chal-2/metrics"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/moviepb"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+	"github.com/sglkc/roketin-be-test/chal-2/validators"
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// serves moviepb.MovieService, every method mirrors a handler of
+// controllers/movie_controller.go
+type MovieServer struct {
+	moviepb.UnimplementedMovieServiceServer
+}
+
+func (*MovieServer) Get(ctx context.Context, req *moviepb.GetMovieRequest) (*moviepb.Movie, error) {
+	languages, err := contentLanguages(req.GetLang())
+	if err != nil {
+		return nil, err
+	}
+
+	movie := database.FindMovieById(ctx, int(req.GetId()))
+	if movie == nil {
+		return nil, problem(dto.CodeMovieNotFound, nil, "No movie with ID %d", req.GetId())
+	}
+
+	return toMovie(movie.Localize(languages...)), nil
+}
+
+func (*MovieServer) List(ctx context.Context, req *moviepb.ListMoviesRequest) (*moviepb.MoviePage, error) {
+	languages, err := contentLanguages(req.GetLang())
+	if err != nil {
+		return nil, err
+	}
+
+	movies := database.FindMovies(ctx)
+
+	return toPage(ctx, movies, languages, req.GetPage(), req.GetLimit()), nil
+}
+
+func (*MovieServer) Search(ctx context.Context, req *moviepb.SearchMoviesRequest) (*moviepb.MoviePage, error) {
+	languages, err := contentLanguages(req.GetLang())
+	if err != nil {
+		return nil, err
+	}
+
+	movies := database.SearchMovies(ctx, database.MovieFilter{
+		Title:         req.GetTitle(),
+		Description:   req.GetDescription(),
+		Artist:        req.GetArtist(),
+		Character:     req.GetCharacter(),
+		Role:          fromRole(req.GetRole()),
+		Genre:         req.GetGenre(),
+		YearFrom:      int(req.GetYearFrom()),
+		YearTo:        int(req.GetYearTo()),
+		Language:      req.GetLanguage(),
+		Country:       req.GetCountry(),
+		Certification: req.GetCertification(),
+	})
+	metrics.SearchResults.Observe(float64(len(movies)))
+
+	return toPage(ctx, movies, languages, req.GetPage(), req.GetLimit()), nil
+}
+
+func (*MovieServer) Create(ctx context.Context, req *moviepb.CreateMovieRequest) (*moviepb.Movie, error) {
+	movie, err := movieFromInput(ctx, req.GetMovie())
+	if err != nil {
+		return nil, err
+	}
+
+	movie, err = database.CreateMovie(ctx, movie)
+	if refProblem := referenceProblem(err); refProblem != nil {
+		return nil, refProblem
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create movie", "error", err)
+		return nil, problem(dto.CodeInternalError, nil, "Failed to create movie")
+	}
+
+	slog.InfoContext(ctx, "movie created", "movie_id", movie.Id)
+	return toMovie(movie.Localize()), nil
+}
+
+func (*MovieServer) Update(ctx context.Context, req *moviepb.UpdateMovieRequest) (*moviepb.Movie, error) {
+	movie, err := movieFromInput(ctx, req.GetMovie())
+	if err != nil {
+		return nil, err
+	}
+
+	movie, err = database.UpdateMovie(ctx, int(req.GetId()), movie)
+	if refProblem := referenceProblem(err); refProblem != nil {
+		return nil, refProblem
+	}
+
+	if errors.Is(err, database.ErrMovieNotFound) {
+		return nil, problem(dto.CodeMovieNotFound, nil, "No movie with ID %d", req.GetId())
+	}
+
+	if errors.Is(err, database.ErrMovieIdExists) {
+		return nil, problem(dto.CodeMovieExists, nil, "Movie with updated ID already exists")
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update movie", "error", err)
+		return nil, problem(dto.CodeInternalError, nil, "Failed to update movie")
+	}
+
+	slog.InfoContext(ctx, "movie updated", "movie_id", req.GetId())
+	return toMovie(movie.Localize()), nil
+}
+
+func (*MovieServer) Delete(ctx context.Context, req *moviepb.DeleteMovieRequest) (*moviepb.DeleteMovieResponse, error) {
+	err := database.DeleteMovie(ctx, int(req.GetId()))
+	if errors.Is(err, database.ErrMovieNotFound) {
+		return nil, problem(dto.CodeMovieNotFound, nil, "No movie with ID %d", req.GetId())
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete movie", "error", err)
+		return nil, problem(dto.CodeInternalError, nil, "Failed to delete movie")
+	}
+
+	slog.InfoContext(ctx, "movie deleted", "movie_id", req.GetId())
+	return &moviepb.DeleteMovieResponse{}, nil
+}
+
+// the change feed of GET /movies/events, ends when the client leaves or the
+// feed closes on shutdown
+func (*MovieServer) Watch(req *moviepb.WatchMoviesRequest, stream moviepb.MovieService_WatchServer) error {
+	movieIds := toInts(req.GetMovieIds())
+	genreIds := toInts(req.GetGenreIds())
+
+	filter := feed.MovieFilter(movieIds, genreIds)
+
+	var sub *feed.Subscription
+	var backlog []feed.Entry
+	var latest uint64
+	complete := true
+
+	if req.GetAfterSeq() > 0 {
+		sub, backlog, latest, complete = feed.Resume(req.GetAfterSeq(), filter)
+	} else {
+		sub = feed.Subscribe(filter)
+	}
+	defer sub.Close()
+
+	if !complete {
+		resync := &moviepb.MovieEvent{Seq: latest, Type: moviepb.MovieEvent_TYPE_RESYNC}
+		if err := stream.Send(resync); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range backlog {
+		if err := stream.Send(toEvent(entry)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case entry, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.Send(toEvent(entry)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// validate the input like the body of POST /movies and resolve its artists
+// and genres into the movie to store
+func movieFromInput(ctx context.Context, input *moviepb.MovieInput) (models.Movie, error) {
+	request := dto.MovieRequest{
+		Id:                  int(input.GetId()),
+		Title:               input.GetTitle(),
+		OriginalTitle:       input.GetOriginalTitle(),
+		Tagline:             input.GetTagline(),
+		Description:         input.GetDescription(),
+		Duration:            int(input.GetDuration()),
+		ReleaseDate:         input.GetReleaseDate(),
+		OriginalLanguage:    input.GetOriginalLanguage(),
+		ProductionCountries: input.GetProductionCountries(),
+		AgeCertification:    input.GetAgeCertification(),
+		Genres:              fromReferences(input.GetGenres()),
+	}
+
+	// proto3 lists are never missing, an empty one counts as not given so
+	// either credits or artists is required like in the JSON body
+	if len(input.GetArtists()) > 0 {
+		request.Artists = fromReferences(input.GetArtists())
+	}
+
+	for _, credit := range input.GetCredits() {
+		request.Credits = append(request.Credits, dto.CreditRequest{
+			Artist:    fromReference(credit.GetArtist()),
+			Role:      fromRole(credit.GetRole()),
+			Character: credit.GetCharacter(),
+			Order:     int(credit.GetOrder()),
+		})
+	}
+
+	if err := binding.Validator.ValidateStruct(request); err != nil {
+		slog.WarnContext(ctx, "invalid movie input", "error", err)
+		return models.Movie{}, problem(dto.CodeValidationFailed, validators.Errors(err, locale(ctx)), "Invalid movie body")
+	}
+
+	credits := request.AllCredits()
+	if len(credits) == 0 {
+		return models.Movie{}, problem(dto.CodeMissingCredit, nil, "Movie must credit at least one artist")
+	}
+
+	refs := make([]models.Reference, len(credits))
+	for i, credit := range credits {
+		refs[i] = credit.Artist
+	}
+
+	var genreIds []int
+	artistIds, err := database.ResolveArtists(ctx, refs)
+	if err == nil {
+		genreIds, err = database.ResolveGenres(ctx, request.Genres)
+	}
+
+	if refProblem := referenceProblem(err); refProblem != nil {
+		return models.Movie{}, refProblem
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to resolve movie references", "error", err)
+		return models.Movie{}, problem(dto.CodeInternalError, nil, "Failed to resolve movie references")
+	}
+
+	return request.Movie(artistIds, genreIds), nil
+}
+
+// the problem for an error about an artist or genre the movie refers to, nil
+// for any other error. Stored movies are checked again, one may be deleted
+// after the input was resolved.
+func referenceProblem(err error) error {
+	code, detail, args, ok := utils.ReferenceProblem(err)
+	if !ok {
+		return nil
+	}
+
+	return problem(code, nil, detail, args...)
+}
+
+// the language of the accept-language metadata like middlewares.Locale
+// negotiates it for requests
+func locale(ctx context.Context) language.Tag {
+	return i18n.Negotiate(strings.Join(metadata.ValueFromIncomingContext(ctx, "accept-language"), ", "))
+}
+
+func contentLanguages(lang string) ([]string, error) {
+	if lang == "" {
+		return nil, nil
+	}
+
+	if !validators.IsLanguageCode(lang) {
+		return nil, problem(dto.CodeInvalidLanguage, nil, "Language %q must be an ISO 639-1 code", lang)
+	}
+
+	return []string{lang}, nil
+}
+
+func toPage(ctx context.Context, movies models.Movies, languages []string, page, limit int32) *moviepb.MoviePage {
+	data, pageInt, limitInt := utils.PageOf(ctx, movies, int(page), int(limit))
+	metrics.PaginationLimit.Observe(float64(limitInt))
+
+	result := &moviepb.MoviePage{
+		Movies: make([]*moviepb.Movie, len(data)),
+		Page:   int32(pageInt),
+		Limit:  int32(limitInt),
+		Count:  int32(len(movies)),
+	}
+
+	for i, movie := range data {
+		result.Movies[i] = toMovie(movie.Localize(languages...))
+	}
+
+	return result
+}
+
+func toMovie(movie models.Movie) *moviepb.Movie {
+	result := &moviepb.Movie{
+		Id:                  int32(movie.Id),
+		Title:               movie.Title,
+		OriginalTitle:       movie.OriginalTitle,
+		Tagline:             movie.Tagline,
+		Description:         movie.Description,
+		Duration:            int32(movie.Duration),
+		ReleaseDate:         movie.ReleaseDate,
+		OriginalLanguage:    movie.OriginalLanguage,
+		ProductionCountries: movie.ProductionCountries,
+		AgeCertification:    movie.AgeCertification,
+		Credits:             make([]*moviepb.Credit, len(movie.Credits)),
+		GenreIds:            make([]int32, len(movie.GenreIds)),
+		Language:            movie.Language,
+	}
+
+	for i, credit := range movie.Credits {
+		result.Credits[i] = &moviepb.Credit{
+			ArtistId:  int32(credit.ArtistId),
+			Role:      toRole(credit.Role),
+			Character: credit.Character,
+			Order:     int32(credit.Order),
+		}
+	}
+
+	for i, id := range movie.GenreIds {
+		result.GenreIds[i] = int32(id)
+	}
+
+	return result
+}
+
+var eventTypes = map[models.EventType]moviepb.MovieEvent_Type{
+	models.EventMovieCreated: moviepb.MovieEvent_TYPE_CREATED,
+	models.EventMovieUpdated: moviepb.MovieEvent_TYPE_UPDATED,
+	models.EventMovieDeleted: moviepb.MovieEvent_TYPE_DELETED,
+}
+
+func toEvent(entry feed.Entry) *moviepb.MovieEvent {
+	event := &moviepb.MovieEvent{
+		Seq:        entry.Seq,
+		Id:         entry.Event.Id,
+		Type:       eventTypes[entry.Event.Type],
+		OccurredAt: timestamppb.New(entry.Event.OccurredAt),
+		MovieId:    int32(entry.Event.MovieId),
+	}
+
+	if entry.Event.Movie != nil {
+		event.Movie = toMovie(*entry.Event.Movie)
+	}
+
+	return event
+}
+
+var roles = map[models.Role]moviepb.Role{
+	models.RoleActor:    moviepb.Role_ROLE_ACTOR,
+	models.RoleDirector: moviepb.Role_ROLE_DIRECTOR,
+	models.RoleWriter:   moviepb.Role_ROLE_WRITER,
+	models.RoleComposer: moviepb.Role_ROLE_COMPOSER,
+}
+
+func toRole(role models.Role) moviepb.Role {
+	return roles[role]
+}
+
+// unspecified is no role, which fails validation where one is required
+func fromRole(role moviepb.Role) models.Role {
+	for modelRole, pbRole := range roles {
+		if pbRole == role {
+			return modelRole
+		}
+	}
+
+	return ""
+}
+
+func fromReference(ref *moviepb.Reference) models.Reference {
+	if id, ok := ref.GetRef().(*moviepb.Reference_Id); ok {
+		return models.Reference{Id: int(id.Id)}
+	}
+
+	return models.Reference{Name: ref.GetName()}
+}
+
+func fromReferences(refs []*moviepb.Reference) []models.Reference {
+	result := make([]models.Reference, len(refs))
+	for i, ref := range refs {
+		result[i] = fromReference(ref)
+	}
+
+	return result
+}
+
+func toInts(ids []int32) []int {
+	result := make([]int, len(ids))
+	for i, id := range ids {
+		result[i] = int(id)
+	}
+
+	return result
+}
+
+// status codes by error code, anything else is internal
+var grpcCodes = map[dto.ErrorCode]codes.Code{
+	dto.CodeInvalidId:        codes.InvalidArgument,
+	dto.CodeValidationFailed: codes.InvalidArgument,
+	dto.CodeMissingCredit:    codes.InvalidArgument,
+	dto.CodeInvalidLanguage:  codes.InvalidArgument,
+	dto.CodeUnknownArtist:    codes.InvalidArgument,
+	dto.CodeUnknownGenre:     codes.InvalidArgument,
+	dto.CodeMovieNotFound:    codes.NotFound,
+	dto.CodeMovieExists:      codes.AlreadyExists,
+	dto.CodeUnauthorized:     codes.Unauthenticated,
+}
+
+// the status for a problem the REST API would respond with, the error code
+// is the reason of an ErrorInfo detail and invalid fields are listed in a
+// BadRequest detail
+// https://google.aip.dev/193
+func problem(code dto.ErrorCode, fieldErrors []dto.FieldError, detail string, args ...any) error {
+	grpcCode, ok := grpcCodes[code]
+	if !ok {
+		grpcCode = codes.Internal
+	}
+
+	st := status.New(grpcCode, fmt.Sprintf(detail, args...))
+	info := &errdetails.ErrorInfo{Reason: string(code), Domain: "movies-api"}
+
+	details := []protoadapt.MessageV1{info}
+
+	if len(fieldErrors) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, fieldError := range fieldErrors {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fieldError.Field,
+				Description: fieldError.Message,
+				Reason:      fieldError.Rule,
+			})
+		}
+
+		details = append(details, badRequest)
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
@@ -0,0 +1,193 @@
+// Package rpc serves the movie API over gRPC on its own port, backed by the
+// same database as the REST controllers.
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/moviepb"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"github.com/sglkc/roketin-be-test/chal-2/utils"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// calls are authenticated like HTTP requests, with a bearer JWT signed with
+// the secret in the authorization metadata
+// https://grpc.io/docs/languages/go/basics/
+func NewServer(secret string, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptor, authUnaryInterceptor(secret)),
+		grpc.ChainStreamInterceptor(streamInterceptor, authStreamInterceptor(secret)),
+	}, opts...)
+
+	srv := grpc.NewServer(opts...)
+	moviepb.RegisterMovieServiceServer(srv, &MovieServer{})
+
+	return srv
+}
+
+// serve on addr in the background, over TLS when both files are given. The
+// returned function stops the server and is meant to be a shutdown hook. It
+// waits for in-flight calls until ctx is done, then cuts off the rest.
+func Start(addr, secret, certFile, keyFile string) (func(context.Context) error, error) {
+	var opts []grpc.ServerOption
+	if certFile != "" && keyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load tls: %w", err)
+		}
+
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", addr, err)
+	}
+
+	srv := NewServer(secret, opts...)
+
+	go func() {
+		slog.Info("Serving gRPC", "addr", listener.Addr().String())
+
+		if err := srv.Serve(listener); err != nil {
+			slog.Error("grpc server stopped", "error", err)
+		}
+	}()
+
+	return func(ctx context.Context) error {
+		stopped := make(chan struct{})
+
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			srv.Stop()
+			return ctx.Err()
+		}
+	}, nil
+}
+
+func unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	ctx, end := startCall(ctx, info.FullMethod)
+	defer func() { end(recover(), &err) }()
+
+	return handler(ctx, req)
+}
+
+func streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx, end := startCall(stream.Context(), info.FullMethod)
+	defer func() { end(recover(), &err) }()
+
+	return handler(srv, &serverStream{stream, ctx})
+}
+
+func authUnaryInterceptor(secret string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func authStreamInterceptor(secret string) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), secret)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{stream, ctx})
+	}
+}
+
+// attach the actor of the call like middlewares.Auth does for requests, calls
+// without a token or without a secret to verify it with are anonymous
+func authenticate(ctx context.Context, secret string) (context.Context, error) {
+	var token string
+	for _, value := range metadata.ValueFromIncomingContext(ctx, "authorization") {
+		if bearer, found := strings.CutPrefix(value, "Bearer "); found {
+			token = bearer
+		}
+	}
+
+	if token == "" || secret == "" {
+		return database.WithActor(ctx, database.AnonymousActor), nil
+	}
+
+	actor, err := utils.TokenActor(secret, token)
+	if err != nil {
+		slog.WarnContext(ctx, "invalid auth token", "error", err)
+		return nil, problem(dto.CodeUnauthorized, nil, "The bearer token is invalid or expired")
+	}
+
+	return database.WithActor(ctx, actor), nil
+}
+
+// trace and log a call like the HTTP middlewares do for requests, the
+// returned function ends it and turns a panic into an internal error
+func startCall(ctx context.Context, method string) (context.Context, func(recovered any, err *error)) {
+	ctx, span := tracing.Tracer.Start(ctx, method)
+	start := time.Now()
+
+	return ctx, func(recovered any, err *error) {
+		defer span.End()
+
+		if recovered != nil {
+			slog.ErrorContext(ctx, "panic recovered", "method", method, "panic", recovered)
+			*err = status.Error(codes.Internal, "internal server error")
+		}
+
+		code := status.Code(*err)
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+		if code != codes.OK {
+			span.SetStatus(otelcodes.Error, code.String())
+		}
+
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK, codes.Canceled:
+		case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+
+		slog.LogAttrs(ctx, level, "call completed",
+			slog.String("method", method),
+			slog.String("code", code.String()),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		)
+	}
+}
+
+// a server stream with the context of the call
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *serverStream) Context() context.Context {
+	return stream.ctx
+}
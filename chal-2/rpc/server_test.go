@@ -0,0 +1,349 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/feed"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/moviepb"
+	"github.com/sglkc/roketin-be-test/chal-2/rpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// serve the movie service over an in-memory connection and return a client
+// of it, the seeded movies are there to read
+// https://pkg.go.dev/google.golang.org/grpc/test/bufconn
+func dial(t *testing.T) moviepb.MovieServiceClient {
+	t.Helper()
+
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	srv := rpc.NewServer(testSecret)
+	go srv.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+	})
+
+	return moviepb.NewMovieServiceClient(conn)
+}
+
+// check the status code and the REST error code in its ErrorInfo, returning
+// the invalid fields of its BadRequest if any
+func assertProblem(t *testing.T, err error, code codes.Code, reason string) []string {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("expected a status error, got %v", err)
+	}
+
+	if st.Code() != code {
+		t.Errorf("expected code %s, got %s: %s", code, st.Code(), st.Message())
+	}
+
+	var fields []string
+	var gotReason string
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			gotReason = detail.GetReason()
+		case *errdetails.BadRequest:
+			for _, violation := range detail.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+
+	if gotReason != reason {
+		t.Errorf("expected reason %s, got %q", reason, gotReason)
+	}
+
+	return fields
+}
+
+func TestGetListAndSearch(t *testing.T) {
+	client := dial(t)
+	ctx := context.Background()
+
+	movie, err := client.Get(ctx, &moviepb.GetMovieRequest{Id: 1})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	stored := database.FindMovieById(ctx, 1)
+	if movie.GetTitle() != stored.Title || len(movie.GetCredits()) != len(stored.Credits) {
+		t.Errorf("expected movie 1 %q with %d credits, got %q with %d", stored.Title, len(stored.Credits),
+			movie.GetTitle(), len(movie.GetCredits()))
+	}
+
+	if movie.GetCredits()[0].GetRole() != moviepb.Role_ROLE_ACTOR {
+		t.Errorf("expected the first credit to be an actor, got %s", movie.GetCredits()[0].GetRole())
+	}
+
+	_, err = client.Get(ctx, &moviepb.GetMovieRequest{Id: 9999})
+	assertProblem(t, err, codes.NotFound, "MOVIE_NOT_FOUND")
+
+	_, err = client.Get(ctx, &moviepb.GetMovieRequest{Id: 1, Lang: "english"})
+	assertProblem(t, err, codes.InvalidArgument, "INVALID_LANGUAGE")
+
+	page, err := client.List(ctx, &moviepb.ListMoviesRequest{Limit: 1})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+
+	if len(page.GetMovies()) != 1 || page.GetPage() != 1 || int(page.GetCount()) != database.CountMovies(ctx) {
+		t.Errorf("expected the first of %d movies, got %d movies on page %d of %d", database.CountMovies(ctx),
+			len(page.GetMovies()), page.GetPage(), page.GetCount())
+	}
+
+	found, err := client.Search(ctx, &moviepb.SearchMoviesRequest{Title: stored.Title[:5]})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+
+	if found.GetCount() == 0 || found.GetMovies()[0].GetId() != 1 {
+		t.Errorf("expected the search for %q to find movie 1, got %v", stored.Title[:5], found.GetMovies())
+	}
+}
+
+func TestCreateUpdateDelete(t *testing.T) {
+	client := dial(t)
+	ctx := context.Background()
+
+	input := &moviepb.MovieInput{
+		Title:       "Top Gun: Maverick",
+		Description: "After more than thirty years of service, Maverick is still pushing the envelope.",
+		Duration:    131,
+		Credits: []*moviepb.CreditInput{{
+			Artist:    &moviepb.Reference{Ref: &moviepb.Reference_Name{Name: "Tom Cruise"}},
+			Role:      moviepb.Role_ROLE_ACTOR,
+			Character: "Maverick",
+		}},
+		Genres: []*moviepb.Reference{{Ref: &moviepb.Reference_Name{Name: "Action"}}},
+	}
+
+	created, err := client.Create(ctx, &moviepb.CreateMovieRequest{Movie: input})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if created.GetId() == 0 || created.GetCredits()[0].GetCharacter() != "Maverick" || created.GetCredits()[0].GetOrder() != 1 {
+		t.Errorf("expected the created movie with its credit, got %v", created)
+	}
+
+	input.Title = "Top Gun: Maverick (IMAX)"
+	updated, err := client.Update(ctx, &moviepb.UpdateMovieRequest{Id: created.GetId(), Movie: input})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if stored := database.FindMovieById(ctx, int(created.GetId())); updated.GetTitle() != input.Title || stored.Title != input.Title {
+		t.Errorf("expected the title to be updated to %q, got %q", input.Title, updated.GetTitle())
+	}
+
+	if _, err := client.Delete(ctx, &moviepb.DeleteMovieRequest{Id: created.GetId()}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	_, err = client.Delete(ctx, &moviepb.DeleteMovieRequest{Id: created.GetId()})
+	assertProblem(t, err, codes.NotFound, "MOVIE_NOT_FOUND")
+
+	_, err = client.Update(ctx, &moviepb.UpdateMovieRequest{Id: created.GetId(), Movie: input})
+	assertProblem(t, err, codes.NotFound, "MOVIE_NOT_FOUND")
+}
+
+func TestCreateValidatesLikeRest(t *testing.T) {
+	client := dial(t)
+	ctx := context.Background()
+	count := database.CountMovies(ctx)
+
+	_, err := client.Create(ctx, &moviepb.CreateMovieRequest{Movie: &moviepb.MovieInput{
+		Description:      "No title, duration or artists",
+		OriginalLanguage: "english",
+		Genres:           []*moviepb.Reference{{Ref: &moviepb.Reference_Id{Id: 1}}},
+	}})
+	fields := assertProblem(t, err, codes.InvalidArgument, "VALIDATION_FAILED")
+
+	for _, field := range []string{"title", "duration", "original_language", "credits", "artists"} {
+		found := false
+		for _, got := range fields {
+			found = found || got == field
+		}
+
+		if !found {
+			t.Errorf("expected %s to be reported as invalid, got %v", field, fields)
+		}
+	}
+
+	_, err = client.Create(ctx, &moviepb.CreateMovieRequest{Movie: &moviepb.MovieInput{
+		Title:       "Unknown artist",
+		Description: "Credits an artist that doesn't exist",
+		Duration:    90,
+		Artists:     []*moviepb.Reference{{Ref: &moviepb.Reference_Name{Name: "Nobody"}}},
+		Genres:      []*moviepb.Reference{{Ref: &moviepb.Reference_Id{Id: 1}}},
+	}})
+	assertProblem(t, err, codes.InvalidArgument, "UNKNOWN_ARTIST")
+
+	if database.CountMovies(ctx) != count {
+		t.Errorf("expected invalid movies not to be created")
+	}
+}
+
+// field errors are in the language of the accept-language metadata
+func TestCreateValidationLanguage(t *testing.T) {
+	client := dial(t)
+
+	for lang, expected := range map[string]string{
+		"":                "is required",
+		"id-ID, en;q=0.5": "wajib diisi",
+	} {
+		ctx := context.Background()
+		if lang != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "accept-language", lang)
+		}
+
+		_, err := client.Create(ctx, &moviepb.CreateMovieRequest{Movie: &moviepb.MovieInput{
+			Description: "No title",
+			Duration:    90,
+			Artists:     []*moviepb.Reference{{Ref: &moviepb.Reference_Id{Id: 1}}},
+			Genres:      []*moviepb.Reference{{Ref: &moviepb.Reference_Id{Id: 1}}},
+		}})
+		assertProblem(t, err, codes.InvalidArgument, "VALIDATION_FAILED")
+
+		var descriptions []string
+		for _, detail := range status.Convert(err).Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				for _, violation := range badRequest.GetFieldViolations() {
+					descriptions = append(descriptions, violation.GetDescription())
+				}
+			}
+		}
+
+		if len(descriptions) != 1 || descriptions[0] != expected {
+			t.Errorf("expected the missing title in %q to be %q, got %q", lang, expected, descriptions)
+		}
+	}
+}
+
+func TestWatchResumesAndStreams(t *testing.T) {
+	client := dial(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	movie := database.FindMovieById(ctx, 1)
+	publish := func(movieId int) {
+		feed.Publish(models.NewMovieEvent(models.EventMovieUpdated, movieId, movie))
+	}
+
+	// the first event is only there to resume after
+	publish(1)
+	sub, _, first, _ := feed.Resume(0, func(models.Event) bool { return false })
+	sub.Close()
+	publish(2)
+	publish(1)
+
+	stream, err := client.Watch(ctx, &moviepb.WatchMoviesRequest{MovieIds: []int32{1}, AfterSeq: first})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("recv backlog: %v", err)
+	}
+
+	if event.GetSeq() != first+2 || event.GetMovieId() != 1 || event.GetType() != moviepb.MovieEvent_TYPE_UPDATED {
+		t.Errorf("expected the buffered update of movie 1 at %d, got %v", first+2, event)
+	}
+
+	// the backlog came with the subscription, so this one is streamed live
+	publish(1)
+
+	event, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("recv live: %v", err)
+	}
+
+	if event.GetSeq() != first+3 || event.GetMovie().GetTitle() != movie.Title {
+		t.Errorf("expected the live update at %d, got %v", first+3, event)
+	}
+
+	resumed, err := client.Watch(ctx, &moviepb.WatchMoviesRequest{AfterSeq: first + 1000})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+
+	event, err = resumed.Recv()
+	if err != nil {
+		t.Fatalf("recv resync: %v", err)
+	}
+
+	if event.GetType() != moviepb.MovieEvent_TYPE_RESYNC || event.GetSeq() != first+3 {
+		t.Errorf("expected a resync at %d for an unknown sequence number, got %v", first+3, event)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	client := dial(t)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "alice",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	movie := database.FindMovieById(ctx, 1)
+
+	if _, err := client.Update(ctx, &moviepb.UpdateMovieRequest{Id: 1, Movie: &moviepb.MovieInput{
+		Title:       movie.Title,
+		Description: movie.Description,
+		Duration:    int32(movie.Duration),
+		Credits: []*moviepb.CreditInput{{
+			Artist: &moviepb.Reference{Ref: &moviepb.Reference_Id{Id: int32(movie.Credits[0].ArtistId)}},
+			Role:   moviepb.Role_ROLE_ACTOR,
+		}},
+		Genres: []*moviepb.Reference{{Ref: &moviepb.Reference_Id{Id: int32(movie.GenreIds[0])}}},
+	}}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if entries := database.FindAuditEntries(ctx, database.AuditFilter{MovieId: 1}); len(entries) == 0 || entries[0].Actor != "alice" {
+		t.Errorf("expected the update to be audited under alice, got %+v", entries)
+	}
+
+	invalid := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token+"x")
+	_, err = client.Get(invalid, &moviepb.GetMovieRequest{Id: 1})
+	assertProblem(t, err, codes.Unauthenticated, "UNAUTHORIZED")
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/sglkc/roketin-be-test/chal-2/config"
+	"github.com/sglkc/roketin-be-test/chal-2/shutdown"
+)
+
+// https://pkg.go.dev/net/http#Server
+func New(cfg *config.Config, handler http.Handler) *http.Server {
+	// HTTP/2 is only negotiated over TLS, plain connections stay on HTTP/1.1
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		Protocols:         protocols,
+	}
+}
+
+// serve until ctx is cancelled, then stop accepting connections and wait for
+// in-flight requests up to the shutdown timeout. The shutdown hooks get a
+// timeout of their own afterwards, requests slow to drain don't cut the
+// flushes short.
+func Run(ctx context.Context, srv *http.Server, cfg *config.Config) error {
+	serveErr := make(chan error, 1)
+
+	go func() {
+		var err error
+
+		if cfg.Tls.CertFile != "" {
+			slog.Info("Serving HTTPS", "addr", srv.Addr)
+			err = srv.ListenAndServeTLS(cfg.Tls.CertFile, cfg.Tls.KeyFile)
+		} else {
+			slog.Info("Serving HTTP", "addr", srv.Addr)
+			err = srv.ListenAndServe()
+		}
+
+		serveErr <- err
+	}()
+
+	var err error
+
+	select {
+	case err = <-serveErr:
+		// the listener failed, still run the hooks so nothing is lost
+		slog.Error("server stopped", "error", err)
+	case <-ctx.Done():
+		slog.Info("shutting down, draining in-flight requests", "timeout", cfg.Server.ShutdownTimeout.String())
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := srv.Shutdown(drainCtx); shutdownErr != nil {
+		slog.Error("failed to drain requests", "error", shutdownErr)
+		err = errors.Join(err, shutdownErr)
+	}
+
+	hooksCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if hooksErr := shutdown.Run(hooksCtx); hooksErr != nil {
+		err = errors.Join(err, hooksErr)
+	}
+
+	return err
+}
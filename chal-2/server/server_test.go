@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/config"
+	"github.com/sglkc/roketin-be-test/chal-2/shutdown"
+)
+
+// a free address on loopback for the server to listen on
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+func TestRunDrainsRequestsThenRunsHooks(t *testing.T) {
+	cfg := &config.Config{Addr: freeAddr(t)}
+	cfg.Server.ShutdownTimeout = time.Second
+
+	started := make(chan struct{})
+	var finished sync.WaitGroup
+	finished.Add(1)
+
+	// the request takes part of the shutdown timeout to drain
+	srv := New(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(300 * time.Millisecond)
+		w.Write([]byte("done"))
+		finished.Done()
+	}))
+
+	var mu sync.Mutex
+	var ran []string
+	hook := func(name string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			finished.Wait()
+
+			// the hooks have a timeout of their own
+			if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) < 900*time.Millisecond {
+				t.Errorf("expected hook %s to get the whole shutdown timeout, %s left", name, time.Until(deadline))
+			}
+
+			mu.Lock()
+			ran = append(ran, name)
+			mu.Unlock()
+
+			return nil
+		}
+	}
+	shutdown.Register("first", hook("first"))
+	shutdown.Register("second", hook("second"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- Run(ctx, srv, cfg) }()
+
+	status := make(chan int, 1)
+	go func() {
+		// retried until the server listens
+		for {
+			resp, err := http.Get("http://" + cfg.Addr)
+			if err == nil {
+				resp.Body.Close()
+				status <- resp.StatusCode
+				return
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the request")
+	}
+	cancel()
+
+	if code := <-status; code != http.StatusOK {
+		t.Errorf("expected the in-flight request to finish with 200, got %d", code)
+	}
+
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Errorf("expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the server to stop")
+	}
+
+	// in reverse order of registration
+	if !slices.Equal(ran, []string{"second", "first"}) {
+		t.Errorf("expected the hooks to run second then first, got %v", ran)
+	}
+}
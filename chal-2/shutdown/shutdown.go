@@ -0,0 +1,53 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+type hook struct {
+	name string
+	fn   func(context.Context) error
+}
+
+var (
+	mu    sync.Mutex
+	hooks []hook
+)
+
+// register a function to run after the server stops accepting requests, for
+// example flushing a store or exporter. Hooks run in reverse order of
+// registration, so a dependency registered first is closed last.
+func Register(name string, fn func(context.Context) error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	hooks = append(hooks, hook{name, fn})
+}
+
+// run every registered hook once, even if some of them fail
+func Run(ctx context.Context) error {
+	mu.Lock()
+	pending := hooks
+	hooks = nil
+	mu.Unlock()
+
+	var errs []error
+
+	for i := len(pending) - 1; i >= 0; i-- {
+		h := pending[i]
+
+		if err := h.fn(ctx); err != nil {
+			slog.Error("shutdown hook failed", "hook", h.name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			continue
+		}
+
+		slog.Info("shutdown hook completed", "hook", h.name)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// https://opentelemetry.io/docs/languages/go/getting-started/
+
+const ServiceName = "movies-api"
+
+// spans started before Setup are no-ops, the global provider delegates to the
+// real one once it is registered
+var Tracer trace.Tracer = otel.Tracer("github.com/sglkc/roketin-be-test/chal-2")
+
+// create a span exporter by name, "otlp" reads the collector endpoint from the
+// standard OTEL_EXPORTER_OTLP_* environment variables and "stdout" writes
+// JSON spans to w
+func NewExporter(ctx context.Context, name string, w io.Writer) (sdktrace.SpanExporter, error) {
+	switch strings.ToLower(name) {
+	case "otlp":
+		return otlptracehttp.New(ctx)
+	case "stdout", "console":
+		return stdouttrace.New(stdouttrace.WithWriter(w))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", name)
+	}
+}
+
+// register the global tracer provider and propagator, an empty or "none"
+// exporter disables tracing. The returned function flushes pending spans.
+func Setup(ctx context.Context, exporterName string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if exporterName == "" || exporterName == "none" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := NewExporter(ctx, exporterName, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
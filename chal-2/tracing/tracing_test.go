@@ -0,0 +1,122 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/middlewares"
+	"github.com/sglkc/roketin-be-test/chal-2/routes"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// subset of the span JSON written by the stdout exporter
+type exportedSpan struct {
+	Name        string
+	SpanContext struct{ SpanID string }
+	Parent      struct{ SpanID string }
+	Attributes  []struct {
+		Key   string
+		Value struct{ Value any }
+	}
+}
+
+func (s exportedSpan) attribute(key string) any {
+	for _, attribute := range s.Attributes {
+		if attribute.Key == key {
+			return attribute.Value.Value
+		}
+	}
+
+	return nil
+}
+
+func TestSearchSpans(t *testing.T) {
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	var buffer bytes.Buffer
+
+	exporter, err := tracing.NewExporter(context.Background(), "stdout", &buffer)
+	if err != nil {
+		t.Fatalf("failed to create exporter: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middlewares.Tracing())
+	routes.RegisterMovieRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/search?title=final&limit=1", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("failed to flush spans: %v", err)
+	}
+
+	spans := map[string]exportedSpan{}
+	decoder := json.NewDecoder(&buffer)
+
+	for {
+		var span exportedSpan
+		if err := decoder.Decode(&span); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("failed to decode span: %v", err)
+		}
+
+		spans[span.Name] = span
+	}
+
+	request, ok := spans["/movies/search"]
+	if !ok {
+		t.Fatalf("expected request span, got %v", spans)
+	}
+
+	if title := request.attribute("movie.search.title"); title != "final" {
+		t.Errorf("expected title query attribute, got %v", title)
+	}
+
+	for _, name := range []string{"database.SearchMovies", "utils.Paginate"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("expected %s span", name)
+			continue
+		}
+
+		if span.Parent.SpanID != request.SpanContext.SpanID {
+			t.Errorf("expected %s to be a child of the request span", name)
+		}
+	}
+
+	// JSON numbers are decoded as float64
+	if results := spans["database.SearchMovies"].attribute("db.result_count"); results != float64(2) {
+		t.Errorf("expected 2 search results, got %v", results)
+	}
+
+	if limit := spans["utils.Paginate"].attribute("pagination.limit"); limit != float64(1) {
+		t.Errorf("expected pagination limit 1, got %v", limit)
+	}
+}
+
+func TestUnknownExporter(t *testing.T) {
+	if _, err := tracing.NewExporter(context.Background(), "jaeger", io.Discard); err == nil {
+		t.Error("expected unknown exporter to fail")
+	}
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/i18n"
+	"golang.org/x/text/language"
+)
+
+// LocaleKey is the gin context key holding the negotiated language
+const LocaleKey = "locale"
+
+// language negotiated for the request, the default one when the locale
+// middleware didn't run
+func Locale(c *gin.Context) language.Tag {
+	if tag, ok := c.Get(LocaleKey); ok {
+		return tag.(language.Tag)
+	}
+
+	return i18n.Supported[0]
+}
+
+// translate a message to the request's language, formatting it with args
+// like fmt.Sprintf
+func T(c *gin.Context, format string, args ...any) string {
+	return i18n.Sprintf(Locale(c), format, args...)
+}
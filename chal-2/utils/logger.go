@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIdKey is the gin context key holding the current request ID
+const RequestIdKey = "requestId"
+
+// ActorKey is the gin context key holding who is making the request
+const ActorKey = "actor"
+
+// https://pkg.go.dev/log/slog
+func NewLogger(level string) *slog.Logger {
+	var slogLevel slog.Level
+
+	switch strings.ToLower(level) {
+	case "debug":
+		slogLevel = slog.LevelDebug
+	case "warn", "warning":
+		slogLevel = slog.LevelWarn
+	case "error":
+		slogLevel = slog.LevelError
+	default:
+		slogLevel = slog.LevelInfo
+	}
+
+	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slogLevel,
+	}))
+}
+
+// get the default logger with the request ID attached, so every line can be
+// traced back to the request that produced it
+func Logger(c *gin.Context) *slog.Logger {
+	logger := slog.Default().With("request_id", c.GetString(RequestIdKey))
+
+	// link log lines to the trace when tracing is enabled
+	if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+		logger = logger.With("trace_id", spanContext.TraceID().String())
+	}
+
+	return logger
+}
@@ -1,33 +1,50 @@
 package utils
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// overridden from the config at startup
+var (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// page through the items with the page and limit query parameters
 // https://go.dev/tour/generics/1
 func Paginate[T any](c *gin.Context, items []T) (data []T, pageInt, limitInt int) {
-	var err error
-	page := c.Query("page")
-	limit := c.Query("limit")
+	pageInt, _ = strconv.Atoi(c.Query("page"))
+	limitInt, _ = strconv.Atoi(c.Query("limit"))
 
-	if page == "" {
-		page = "1"
-	}
-	if limit == "" {
-		limit = "10"
-	}
+	return PageOf(c.Request.Context(), items, pageInt, limitInt)
+}
 
-	pageInt, err = strconv.Atoi(page)
-	if err != nil || pageInt < 1 {
-		pageInt = 1
-	}
+// a page of the items, a page below 1 is the first one and a limit below 1
+// or above MaxLimit is DefaultLimit or MaxLimit
+func PageOf[T any](ctx context.Context, items []T, page, limit int) (data []T, pageInt, limitInt int) {
+	_, span := tracing.Tracer.Start(ctx, "utils.Paginate")
+	defer span.End()
+
+	pageInt = max(page, 1)
 
-	limitInt, err = strconv.Atoi(limit)
-	if err != nil || limitInt < 1 {
-		limitInt = 10
+	limitInt = limit
+	if limitInt < 1 {
+		limitInt = DefaultLimit
 	}
+	if limitInt > MaxLimit {
+		limitInt = MaxLimit
+	}
+
+	span.SetAttributes(
+		attribute.Int("pagination.page", pageInt),
+		attribute.Int("pagination.limit", limitInt),
+		attribute.Int("pagination.count", len(items)),
+	)
 
 	start := (pageInt - 1) * limitInt
 	end := start + limitInt
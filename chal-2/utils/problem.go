@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/validators"
+)
+
+const ProblemContentType = "application/problem+json"
+
+type problemType struct {
+	status int
+	title  string
+}
+
+// every code always comes with the same status and title
+var problemTypes = map[dto.ErrorCode]problemType{
+	dto.CodeInvalidId:        {http.StatusBadRequest, "Invalid ID"},
+	dto.CodeInvalidQuery:     {http.StatusBadRequest, "Invalid query parameter"},
+	dto.CodeMalformedBody:    {http.StatusBadRequest, "Malformed request body"},
+	dto.CodeValidationFailed: {http.StatusBadRequest, "Validation failed"},
+
+	dto.CodeMovieNotFound: {http.StatusNotFound, "Movie not found"},
+	dto.CodeMovieExists:   {http.StatusConflict, "Movie already exists"},
+	dto.CodeMissingCredit: {http.StatusBadRequest, "Movie has no credits"},
+
+	dto.CodeRevisionNotFound: {http.StatusNotFound, "Revision not found"},
+
+	dto.CodeBatchTooLarge: {http.StatusRequestEntityTooLarge, "Batch too large"},
+	dto.CodeBatchAborted:  {http.StatusFailedDependency, "Batch aborted"},
+
+	dto.CodeInvalidLanguage:     {http.StatusBadRequest, "Invalid language"},
+	dto.CodeTranslationNotFound: {http.StatusNotFound, "Translation not found"},
+
+	dto.CodeArtistNotFound: {http.StatusNotFound, "Artist not found"},
+	dto.CodeArtistExists:   {http.StatusConflict, "Artist already exists"},
+	dto.CodeArtistInUse:    {http.StatusConflict, "Artist is in use"},
+	dto.CodeUnknownArtist:  {http.StatusBadRequest, "Unknown artist"},
+
+	dto.CodeGenreNotFound: {http.StatusNotFound, "Genre not found"},
+	dto.CodeGenreExists:   {http.StatusConflict, "Genre already exists"},
+	dto.CodeGenreInUse:    {http.StatusConflict, "Genre is in use"},
+	dto.CodeUnknownGenre:  {http.StatusBadRequest, "Unknown genre"},
+
+	dto.CodeWebhookNotFound:      {http.StatusNotFound, "Webhook not found"},
+	dto.CodeDeadLetterNotFound:   {http.StatusNotFound, "Dead letter not found"},
+	dto.CodeWebhookUrlNotAllowed: {http.StatusBadRequest, "Webhook URL not allowed"},
+
+	dto.CodeUnauthorized:     {http.StatusUnauthorized, "Unauthorized"},
+	dto.CodeRouteNotFound:    {http.StatusNotFound, "Route not found"},
+	dto.CodeMethodNotAllowed: {http.StatusMethodNotAllowed, "Method not allowed"},
+	dto.CodeInternalError:    {http.StatusInternalServerError, "Internal server error"},
+}
+
+// respond with a problem for the code and abort the handler chain, detail
+// explains this occurrence, is translated and formatted with args like
+// fmt.Sprintf, and may be empty
+// https://www.rfc-editor.org/rfc/rfc7807
+func Problem(c *gin.Context, code dto.ErrorCode, detail string, args ...any) {
+	writeProblem(c, NewProblem(c, code, detail, args...))
+}
+
+func writeProblem(c *gin.Context, body dto.Problem) {
+	// set before rendering, gin keeps a content type that is already set
+	c.Header("Content-Type", ProblemContentType)
+	c.Abort()
+	c.IndentedJSON(body.Status, body)
+}
+
+// the problem Problem would respond with, for responses reporting several
+// of them such as batches
+func NewProblem(c *gin.Context, code dto.ErrorCode, detail string, args ...any) dto.Problem {
+	return newProblem(c, code, detail, args, nil)
+}
+
+// the problem BindProblem would respond with
+func NewBindProblem(c *gin.Context, err error, detail string) dto.Problem {
+	fieldErrors := validators.Errors(err, Locale(c))
+	if len(fieldErrors) == 0 {
+		return newProblem(c, dto.CodeMalformedBody, detail, nil, nil)
+	}
+
+	return newProblem(c, dto.CodeValidationFailed, detail, nil, fieldErrors)
+}
+
+func newProblem(c *gin.Context, code dto.ErrorCode, detail string, args []any, fieldErrors []dto.FieldError) dto.Problem {
+	problemType, ok := problemTypes[code]
+	if !ok {
+		problemType = problemTypes[dto.CodeInternalError]
+	}
+
+	return dto.Problem{
+		Type:      ProblemTypeUri(code),
+		Title:     T(c, problemType.title),
+		Status:    problemType.status,
+		Detail:    T(c, detail, args...),
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestId: c.GetString(RequestIdKey),
+		Errors:    fieldErrors,
+	}
+}
+
+// respond to a request body that failed to bind, field errors are listed
+// when the body was valid JSON
+func BindProblem(c *gin.Context, err error, detail string) {
+	writeProblem(c, NewBindProblem(c, err, detail))
+}
+
+// the code and detail of an error about an artist or genre a movie refers
+// to, ok is false for any other error
+func ReferenceProblem(err error) (code dto.ErrorCode, detail string, args []any, ok bool) {
+	var refErr *database.ReferenceError
+	if !errors.As(err, &refErr) {
+		return "", "", nil, false
+	}
+
+	if refErr.Kind == "genre" {
+		return dto.CodeUnknownGenre, "No genre %q", []any{refErr.Ref}, true
+	}
+
+	return dto.CodeUnknownArtist, "No artist %q", []any{refErr.Ref}, true
+}
+
+// problem types aren't meant to be dereferenced, a URN keeps them stable
+// across hosts, e.g. urn:movies-api:problem:movie-not-found
+func ProblemTypeUri(code dto.ErrorCode) string {
+	return "urn:movies-api:problem:" + strings.ReplaceAll(strings.ToLower(string(code)), "_", "-")
+}
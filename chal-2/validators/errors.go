@@ -0,0 +1,133 @@
+package validators
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/i18n"
+	"golang.org/x/text/language"
+)
+
+// translate a binding error into one entry per invalid field in the given
+// language, errors that aren't about a field such as malformed JSON give no
+// entries
+func Errors(err error, tag language.Tag) []dto.FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fieldErrors := make([]dto.FieldError, len(validationErrors))
+		for i, fe := range validationErrors {
+			format, args := message(fe)
+			fieldErrors[i] = dto.FieldError{
+				Field:   fieldName(fe),
+				Rule:    fe.ActualTag(),
+				Message: i18n.Sprintf(tag, format, args...),
+			}
+		}
+
+		return fieldErrors
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		return []dto.FieldError{{
+			Field:   typeError.Field,
+			Rule:    "type",
+			Message: i18n.Translate(tag, typeMessage(typeError.Type.Kind())),
+		}}
+	}
+
+	return nil
+}
+
+// namespace without the request struct, e.g. MovieRequest.credits[0].role
+// becomes credits[0].role
+func fieldName(fe validator.FieldError) string {
+	_, name, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+
+	return name
+}
+
+// the message as a format string to translate and its arguments
+func message(fe validator.FieldError) (string, []any) {
+	param := fe.Param()
+
+	switch fe.ActualTag() {
+	case "required":
+		return "is required", nil
+	case "required_without":
+		return "is required when %s is not given", []any{jsonName(param)}
+	case "required_unless":
+		field, value, _ := strings.Cut(param, " ")
+		return "is required unless %s is %s", []any{jsonName(field), value}
+	case "notblank":
+		return "must not be blank", nil
+	case "min":
+		return boundMessage(fe, "must be at least %s", "must be at least %s characters",
+			"must have at least %s items", "must be at least %s minutes"), []any{param}
+	case "max":
+		return boundMessage(fe, "must be at most %s", "must be at most %s characters",
+			"must have at most %s items", "must be at most %s minutes"), []any{param}
+	case "oneof":
+		return "must be one of %s", []any{strings.Join(strings.Fields(param), ", ")}
+	case "datetime":
+		return "must be a date formatted as YYYY-MM-DD", nil
+	case "iso639_1":
+		return "must be a two letter ISO 639-1 language code", nil
+	case "http_url":
+		return "must be an HTTP or HTTPS URL", nil
+	case "iso3166_1_alpha2":
+		return "must be a two letter ISO 3166-1 country code", nil
+	case "age_certification":
+		return "must be one of %s", []any{strings.Join(ageCertifications, ", ")}
+	case "unique_references", "unique":
+		return "must not list the same item twice", nil
+	case "unique_credits":
+		return "must not credit an artist for the same role and character twice", nil
+	}
+
+	return "is invalid", nil
+}
+
+// min and max count characters of a string, items of a list or minutes of a
+// duration, each worded on its own so they can be translated
+func boundMessage(fe validator.FieldError, number, characters, items, minutes string) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return characters
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return items
+	}
+
+	if fe.Field() == "duration" {
+		return minutes
+	}
+
+	return number
+}
+
+// struct field names in params, e.g. required_without=Artists, as JSON names
+func jsonName(field string) string {
+	return strings.ToLower(field)
+}
+
+func typeMessage(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "must be a string"
+	case reflect.Slice, reflect.Array:
+		return "must be a list"
+	case reflect.Map, reflect.Struct:
+		return "must be an object"
+	case reflect.Bool:
+		return "must be a boolean"
+	}
+
+	return "must be a number"
+}
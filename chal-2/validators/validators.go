@@ -0,0 +1,184 @@
+// Package validators registers custom binding tags on gin's validator and
+// translates its errors into field level details for the error response.
+package validators
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"golang.org/x/text/language"
+)
+
+const (
+	// longest movie we accept, in minutes
+	MaxDuration = 1440
+	// longest movie title we accept, in characters
+	MaxTitleLength = 200
+)
+
+// https://pkg.go.dev/github.com/go-playground/validator/v10#hdr-Custom_Validation_Functions
+func init() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// report fields by their JSON name, e.g. credits[0].role
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	// a reference is validated as its ID or name, so required rejects an
+	// empty one instead of resolving it to an unknown artist without a name
+	validate.RegisterCustomTypeFunc(referenceValue, models.Reference{})
+
+	validate.RegisterValidation("notblank", isNotBlank)
+	validate.RegisterValidation("iso639_1", isIso6391)
+	validate.RegisterValidation("age_certification", isAgeCertification)
+	validate.RegisterValidation("unique_references", hasUniqueReferences)
+	validate.RegisterStructValidation(validateMovieRequest, dto.MovieRequest{})
+
+	// https://pkg.go.dev/github.com/go-playground/validator/v10#hdr-Alias_Validators
+	validate.RegisterAlias("movie_title", "notblank,max="+strconv.Itoa(MaxTitleLength))
+	validate.RegisterAlias("movie_duration", "min=1,max="+strconv.Itoa(MaxDuration))
+}
+
+// strings made of only whitespace are as good as empty
+func isNotBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
+
+func isIso6391(fl validator.FieldLevel) bool {
+	return IsLanguageCode(fl.Field().String())
+}
+
+// two letter ISO 639-1 language code, e.g. en or id
+func IsLanguageCode(code string) bool {
+	if len(code) != 2 || strings.ToLower(code) != code {
+		return false
+	}
+
+	_, err := language.ParseBase(code)
+	return err == nil
+}
+
+// US (MPA) and Indonesian (LSF) ratings, the markets we publish to
+var ageCertifications = []string{
+	"G", "PG", "PG-13", "R", "NC-17",
+	"SU", "13+", "17+", "21+",
+}
+
+func isAgeCertification(fl validator.FieldLevel) bool {
+	return slices.Contains(ageCertifications, fl.Field().String())
+}
+
+func referenceValue(field reflect.Value) any {
+	ref := field.Interface().(models.Reference)
+	if ref.Id != 0 {
+		return ref.Id
+	}
+
+	return strings.TrimSpace(ref.Name)
+}
+
+// the same artist or genre can't be listed twice, by ID or by name. The
+// param is the kind of record, e.g. unique_references=artist
+func hasUniqueReferences(fl validator.FieldLevel) bool {
+	refs, ok := fl.Field().Interface().([]models.Reference)
+	if !ok {
+		return false
+	}
+
+	lookup := lookups[fl.Param()]
+	if lookup == nil {
+		return false
+	}
+
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		key := referenceKey(lookup, ref)
+		if seen[key] {
+			return false
+		}
+
+		seen[key] = true
+	}
+
+	return true
+}
+
+// checked on the whole request so the credits are also validated one by one,
+// a failing tag before dive would skip them
+func validateMovieRequest(sl validator.StructLevel) {
+	request := sl.Current().Interface().(dto.MovieRequest)
+
+	credits := request.AllCredits()
+
+	i := duplicateCredit(credits)
+	if i == -1 {
+		return
+	}
+
+	// the artists are credited after the credits, a duplicate within them is
+	// already reported by unique_references
+	if i < len(request.Credits) {
+		sl.ReportError(request.Credits, "credits", "Credits", "unique_credits", "")
+	} else if duplicateCredit(credits[len(request.Credits):]) == -1 {
+		sl.ReportError(request.Artists, "artists", "Artists", "unique_credits", "")
+	}
+}
+
+// the index of the first credit repeating an earlier one, or -1. An artist
+// can have several credits on a movie, e.g. director and writer, but not the
+// same role as the same character twice
+func duplicateCredit(credits []dto.CreditRequest) int {
+	seen := map[[3]string]bool{}
+	for i, credit := range credits {
+		key := [3]string{
+			referenceKey(database.LookupArtist, credit.Artist),
+			string(credit.Role),
+			strings.ToLower(strings.TrimSpace(credit.Character)),
+		}
+		if seen[key] {
+			return i
+		}
+
+		seen[key] = true
+	}
+
+	return -1
+}
+
+// finds the ID of a record by reference, by the param of unique_references
+var lookups = map[string]func(context.Context, models.Reference) (int, bool){
+	"artist": database.LookupArtist,
+	"genre":  database.LookupGenre,
+}
+
+// references to a known record are compared by its ID so the ID and the name
+// of the same one are a duplicate, unknown ones are left to the resolver and
+// compared as written, names case insensitively like the database does
+func referenceKey(lookup func(context.Context, models.Reference) (int, bool), ref models.Reference) string {
+	if id, ok := lookup(context.Background(), ref); ok {
+		return "#" + strconv.Itoa(id)
+	}
+
+	if ref.Id != 0 {
+		return "#" + strconv.Itoa(ref.Id)
+	}
+
+	return strings.ToLower(strings.TrimSpace(ref.Name))
+}
@@ -0,0 +1,96 @@
+package validators
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/dto"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"golang.org/x/text/language"
+)
+
+// a valid request crediting artist 1 as an actor
+func movieRequest() dto.MovieRequest {
+	return dto.MovieRequest{
+		Title:       "Validated",
+		Description: "A movie made up for validation.",
+		Duration:    100,
+		Artists:     []models.Reference{{Id: 1}},
+		Genres:      []models.Reference{{Id: 1}},
+	}
+}
+
+// the field and rule of every error in the request
+func validationErrors(request dto.MovieRequest) [][2]string {
+	var result [][2]string
+	for _, fe := range Errors(binding.Validator.ValidateStruct(request), language.English) {
+		result = append(result, [2]string{fe.Field, fe.Rule})
+	}
+
+	return result
+}
+
+func TestValidateMovieRequest(t *testing.T) {
+	ctx := context.Background()
+	if err := database.Migrate(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	artist := database.FindArtistById(ctx, 1)
+	genre := database.FindGenreById(ctx, 1)
+	if artist == nil || genre == nil {
+		t.Fatal("expected artist and genre 1 to be seeded")
+	}
+
+	byId := models.Reference{Id: 1}
+	byName := models.Reference{Name: " " + artist.Name + " "}
+
+	for _, test := range []struct {
+		name     string
+		change   func(request *dto.MovieRequest)
+		expected [][2]string
+	}{
+		{"valid", func(*dto.MovieRequest) {}, nil},
+		{"empty credit artist", func(request *dto.MovieRequest) {
+			request.Credits = []dto.CreditRequest{{Role: models.RoleDirector}}
+		}, [][2]string{{"credits[0].artist", "required"}}},
+		{"blank credit artist", func(request *dto.MovieRequest) {
+			request.Credits = []dto.CreditRequest{{Artist: models.Reference{Name: "  "}, Role: models.RoleDirector}}
+		}, [][2]string{{"credits[0].artist", "required"}}},
+		{"empty artist", func(request *dto.MovieRequest) {
+			request.Artists = append(request.Artists, models.Reference{})
+		}, [][2]string{{"artists[1]", "required"}}},
+		{"artist by ID and name", func(request *dto.MovieRequest) {
+			request.Artists = []models.Reference{byId, byName}
+		}, [][2]string{{"artists", "unique_references"}}},
+		{"unknown artists by name", func(request *dto.MovieRequest) {
+			request.Artists = []models.Reference{{Name: "Nobody At All"}, {Name: "nobody at all"}}
+		}, [][2]string{{"artists", "unique_references"}}},
+		{"genre by ID and name", func(request *dto.MovieRequest) {
+			request.Genres = []models.Reference{{Id: 1}, {Name: genre.Name}}
+		}, [][2]string{{"genres", "unique_references"}}},
+		{"credit by ID and name", func(request *dto.MovieRequest) {
+			request.Artists = nil
+			request.Credits = []dto.CreditRequest{
+				{Artist: byId, Role: models.RoleActor, Character: "Hero"},
+				{Artist: byName, Role: models.RoleActor, Character: "hero"},
+			}
+		}, [][2]string{{"credits", "unique_credits"}}},
+		{"same artist in another role", func(request *dto.MovieRequest) {
+			request.Credits = []dto.CreditRequest{{Artist: byName, Role: models.RoleDirector}}
+		}, nil},
+		{"artist also credited as an actor", func(request *dto.MovieRequest) {
+			request.Credits = []dto.CreditRequest{{Artist: byName, Role: models.RoleActor}}
+		}, [][2]string{{"artists", "unique_credits"}}},
+	} {
+		request := movieRequest()
+		test.change(&request)
+
+		if got := validationErrors(request); !slices.Equal(got, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+// hosts deliveries may go to even when they're private, e.g. a receiver on
+// the same network. Set from the config before Start.
+var AllowedHosts []string
+
+var ErrForbiddenTarget = errors.New("webhook target is not a public address")
+
+func allowedHost(host string) bool {
+	return slices.ContainsFunc(AllowedHosts, func(allowed string) bool {
+		return strings.EqualFold(allowed, host)
+	})
+}
+
+// loopback, private, link-local (cloud metadata such as 169.254.169.254
+// included) and other addresses that aren't on the internet
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsValid() && addr.IsGlobalUnicast() && !addr.IsPrivate() && !addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast()
+}
+
+// check that the URL points to a public address or an allowed host, so
+// webhooks can't be used to reach services behind the server. A host that
+// doesn't resolve yet is left to the check made when delivering.
+func CheckUrl(ctx context.Context, rawUrl string) error {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	host := parsed.Hostname()
+	if allowedHost(host) {
+		return nil
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !publicAddr(addr) {
+			return fmt.Errorf("%w: %s", ErrForbiddenTarget, host)
+		}
+
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenTarget, host, addr)
+		}
+	}
+
+	return nil
+}
+
+// dial the receiver, checking the address connected to rather than the one
+// the URL resolved to when it was saved, which could have changed since
+func dial(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{}
+
+	if host, _, err := net.SplitHostPort(address); err != nil || !allowedHost(host) {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !publicAddr(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenTarget, address)
+			}
+
+			return nil
+		}
+	}
+
+	return dialer.DialContext(ctx, network, address)
+}
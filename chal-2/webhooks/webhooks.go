@@ -0,0 +1,364 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/buildinfo"
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/metrics"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// set from the config before Start
+var (
+	MaxAttempts    = 6
+	InitialBackoff = time.Second
+	MaxBackoff     = 5 * time.Minute
+	Timeout        = 10 * time.Second
+)
+
+// headers sent with every delivery, the signature is
+// sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+const (
+	HeaderEventId   = "X-Webhook-Event-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderAttempt   = "X-Webhook-Attempt"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+var errShutdown = errors.New("server shut down before the event was delivered")
+
+// how many deliveries can wait for a worker before new ones are
+// dead-lettered right away
+const queueSize = 1024
+
+type job struct {
+	// carries the trace of the change the event is about, through the
+	// outbox and the bus
+	ctx     context.Context
+	webhook models.Webhook
+	event   models.Event
+	attempt int
+}
+
+var (
+	mu sync.Mutex
+	// nil before Start and after stopping, new events are dropped then
+	jobs chan job
+	// the queue is only closed once nothing is pending, retries still
+	// send to it while stopping
+	queue   chan job
+	stopped chan struct{}
+	pending sync.WaitGroup
+	workers sync.WaitGroup
+	client  = &http.Client{}
+)
+
+// start the workers delivering events, the returned function stops taking
+// new events and waits for pending deliveries. Deliveries still queued or
+// waiting to be retried when the context ends are dead-lettered so they can
+// be redelivered later.
+func Start(count int) func(context.Context) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	queue = make(chan job, queueSize)
+	jobs = queue
+	stopped = make(chan struct{})
+	// no proxy, the receiver's address is checked when dialing it
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dial
+	client = &http.Client{Timeout: Timeout, Transport: transport}
+
+	for range count {
+		workers.Add(1)
+		go work(queue, stopped)
+	}
+
+	return stop
+}
+
+func stop(ctx context.Context) error {
+	mu.Lock()
+	if jobs == nil {
+		mu.Unlock()
+		return nil
+	}
+
+	jobs = nil
+	mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		pending.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		// wake up waiting retries, which dead-letter themselves, and
+		// dead-letter what is still queued instead of delivering it. Only
+		// the deliveries already being sent are waited for.
+		close(stopped)
+		err = ctx.Err()
+
+		for drained := false; !drained; {
+			select {
+			case <-done:
+				drained = true
+			case j := <-queue:
+				deadLetter(j, errShutdown)
+				pending.Done()
+			}
+		}
+	}
+
+	close(queue)
+	workers.Wait()
+
+	return err
+}
+
+// queue the event for every webhook subscribed to it, does nothing until
+// Start is called
+func Publish(ctx context.Context, event models.Event) {
+	for _, webhook := range database.FindSubscribers(ctx, event.Type) {
+		enqueue(job{context.WithoutCancel(ctx), webhook, event, 1})
+	}
+}
+
+// queue a dead letter again starting from the first attempt
+func Redeliver(ctx context.Context, webhook models.Webhook, letter models.DeadLetter) {
+	enqueue(job{context.WithoutCancel(ctx), webhook, letter.Event, 1})
+}
+
+func enqueue(j job) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if jobs == nil {
+		return
+	}
+
+	pending.Add(1)
+
+	select {
+	case jobs <- j:
+	default:
+		pending.Done()
+		go deadLetter(j, errors.New("delivery queue is full"))
+	}
+}
+
+// retries go back on the queue from their own goroutine so a worker never
+// sleeps through a backoff
+func retry(j job, wait time.Duration) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	mu.Lock()
+	incoming, done := queue, stopped
+	mu.Unlock()
+
+	select {
+	case <-timer.C:
+	case <-done:
+		deadLetter(j, errShutdown)
+		pending.Done()
+		return
+	}
+
+	j.attempt++
+	incoming <- j
+}
+
+func work(incoming <-chan job, stopped <-chan struct{}) {
+	defer workers.Done()
+
+	for j := range incoming {
+		select {
+		case <-stopped:
+			deadLetter(j, errShutdown)
+			pending.Done()
+			continue
+		default:
+		}
+
+		err := deliver(j)
+		if err == nil {
+			pending.Done()
+			continue
+		}
+
+		if j.attempt >= MaxAttempts {
+			deadLetter(j, err)
+			pending.Done()
+			continue
+		}
+
+		go retry(j, Backoff(j.attempt))
+	}
+}
+
+// wait before retrying the given attempt, doubled on each attempt up to
+// MaxBackoff with up to half of it as jitter so receivers coming back up
+// aren't hit by every retry at once
+func Backoff(attempt int) time.Duration {
+	// doubled one attempt at a time, shifting a long initial backoff by the
+	// attempt overflows into a negative wait
+	wait := min(InitialBackoff, MaxBackoff)
+	for range attempt - 1 {
+		if wait > MaxBackoff/2 {
+			wait = MaxBackoff
+			break
+		}
+
+		wait *= 2
+	}
+
+	return wait/2 + rand.N(wait/2+1)
+}
+
+// sign a delivery body sent at the unix timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// POST the event once and log the attempt, any status outside 2xx fails
+func deliver(j job) error {
+	ctx, span := tracing.Tracer.Start(j.ctx, "webhooks.deliver", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("webhook.id", j.webhook.Id),
+		attribute.String("webhook.event_id", j.event.Id),
+		attribute.String("webhook.event", string(j.event.Type)),
+		attribute.Int("webhook.attempt", j.attempt),
+	)
+
+	started := time.Now()
+	statusCode, err := post(ctx, j)
+
+	delivery := models.WebhookDelivery{
+		WebhookId:  j.webhook.Id,
+		EventId:    j.event.Id,
+		EventType:  j.event.Type,
+		Attempt:    j.attempt,
+		Status:     models.DeliverySucceeded,
+		StatusCode: statusCode,
+		DurationMs: time.Since(started).Milliseconds(),
+		Timestamp:  started.UTC(),
+	}
+
+	if err != nil {
+		delivery.Status = models.DeliveryFailed
+		delivery.Error = err.Error()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	metrics.WebhookDeliveries.WithLabelValues(string(j.event.Type), string(delivery.Status)).Inc()
+
+	database.AddDelivery(ctx, delivery)
+
+	if err != nil {
+		slog.Warn("webhook delivery failed", "webhook_id", j.webhook.Id, "event_id", j.event.Id,
+			"attempt", j.attempt, "error", err)
+	}
+
+	return err
+}
+
+func post(ctx context.Context, j job) (int, error) {
+	body, err := json.Marshal(j.event)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "movies-api-webhooks/"+buildinfo.Get().Version)
+	req.Header.Set(HeaderEventId, j.event.Id)
+	req.Header.Set(HeaderEvent, string(j.event.Type))
+	req.Header.Set(HeaderAttempt, strconv.Itoa(j.attempt))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(j.webhook.Secret, timestamp, body))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	// drained so the connection can be reused, receivers only need to answer
+	// with a status
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("receiver responded with %s", res.Status)
+	}
+
+	return res.StatusCode, nil
+}
+
+func deadLetter(j job, err error) {
+	_, dbErr := database.AddDeadLetter(j.ctx, models.DeadLetter{
+		WebhookId: j.webhook.Id,
+		Event:     j.event,
+		Attempts:  j.attempt,
+		LastError: err.Error(),
+		FailedAt:  time.Now().UTC(),
+	})
+	if dbErr != nil {
+		slog.Error("failed to dead-letter webhook event", "webhook_id", j.webhook.Id, "event_id", j.event.Id, "error", dbErr)
+		return
+	}
+
+	slog.Warn("webhook event dead-lettered", "webhook_id", j.webhook.Id, "event_id", j.event.Id,
+		"attempts", j.attempt, "error", err)
+}
+
+// random secret for webhooks created without one
+func NewSecret() string {
+	secret := make([]byte, 24)
+	cryptorand.Read(secret)
+
+	return "whsec_" + hex.EncodeToString(secret)
+}
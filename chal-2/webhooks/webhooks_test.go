@@ -0,0 +1,349 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sglkc/roketin-be-test/chal-2/database"
+	"github.com/sglkc/roketin-be-test/chal-2/models"
+	"github.com/sglkc/roketin-be-test/chal-2/webhooks"
+)
+
+const secret = "whsec_test_secret_0123456789"
+
+// start the workers with backoffs short enough for tests and subscribe a
+// receiver answering with the given statuses in turn, the last one repeats
+func subscribe(t *testing.T, statuses ...int) (models.Webhook, *atomic.Int32, chan received) {
+	t.Helper()
+
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	webhooks.MaxAttempts = 3
+	webhooks.InitialBackoff = time.Millisecond
+	webhooks.MaxBackoff = 5 * time.Millisecond
+	webhooks.Timeout = time.Second
+	// receivers are served on loopback
+	webhooks.AllowedHosts = []string{"127.0.0.1"}
+	stop := webhooks.Start(2)
+
+	var calls atomic.Int32
+	deliveries := make(chan received, 10)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1))
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- received{r.Header, body}
+
+		w.WriteHeader(statuses[min(call, len(statuses))-1])
+	}))
+
+	webhook, err := database.CreateWebhook(context.Background(), models.Webhook{
+		Url:    receiver.URL,
+		Events: []models.EventType{models.EventMovieUpdated},
+		Active: true,
+		Secret: secret,
+	})
+	if err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := stop(ctx); err != nil {
+			t.Errorf("failed to stop webhooks: %v", err)
+		}
+
+		receiver.Close()
+		database.DeleteWebhook(context.Background(), webhook.Id)
+	})
+
+	return webhook, &calls, deliveries
+}
+
+// a request as the receiver got it
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// wait for the webhook to have the given number of delivery attempts logged
+func waitForDeliveries(t *testing.T, webhookId, count int) []models.WebhookDelivery {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		deliveries, err := database.FindDeliveries(context.Background(), webhookId)
+		if err != nil {
+			t.Fatalf("failed to find deliveries: %v", err)
+		}
+
+		if len(deliveries) >= count {
+			return deliveries
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for %d deliveries", count)
+	return nil
+}
+
+func TestDeliverySignature(t *testing.T) {
+	webhook, _, deliveries := subscribe(t, http.StatusNoContent)
+
+	movie := models.Movie{Id: 1, Title: "Signed"}
+	event := models.NewMovieEvent(models.EventMovieUpdated, movie.Id, &movie)
+	webhooks.Publish(context.Background(), event)
+
+	var r received
+	select {
+	case r = <-deliveries:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the delivery")
+	}
+
+	timestamp, err := strconv.ParseInt(r.header.Get(webhooks.HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header %q", r.header.Get(webhooks.HeaderTimestamp))
+	}
+
+	if got, want := r.header.Get(webhooks.HeaderSignature), webhooks.Sign(secret, timestamp, r.body); got != want {
+		t.Errorf("signature %q, want %q", got, want)
+	}
+
+	if got := r.header.Get(webhooks.HeaderEvent); got != string(models.EventMovieUpdated) {
+		t.Errorf("event header %q, want %q", got, models.EventMovieUpdated)
+	}
+
+	var delivered models.Event
+	if err := json.Unmarshal(r.body, &delivered); err != nil {
+		t.Fatalf("invalid delivery body: %v", err)
+	}
+
+	if delivered.Id != event.Id || delivered.Movie == nil || delivered.Movie.Title != "Signed" {
+		t.Errorf("delivered %+v, want event %s of movie Signed", delivered, event.Id)
+	}
+
+	logged := waitForDeliveries(t, webhook.Id, 1)
+	if logged[0].Status != models.DeliverySucceeded || logged[0].StatusCode != http.StatusNoContent {
+		t.Errorf("delivery %+v, want a succeeded 204", logged[0])
+	}
+}
+
+func TestDeliveryIgnoresOtherEvents(t *testing.T) {
+	_, calls, _ := subscribe(t, http.StatusOK)
+
+	webhooks.Publish(context.Background(), models.NewMovieEvent(models.EventMovieDeleted, 1, nil))
+	time.Sleep(50 * time.Millisecond)
+
+	if calls.Load() != 0 {
+		t.Errorf("receiver called %d times for an event it isn't subscribed to", calls.Load())
+	}
+}
+
+func TestDeliveryRetries(t *testing.T) {
+	webhook, calls, _ := subscribe(t, http.StatusInternalServerError, http.StatusOK)
+
+	webhooks.Publish(context.Background(), models.NewMovieEvent(models.EventMovieUpdated, 1, nil))
+
+	deliveries := waitForDeliveries(t, webhook.Id, 2)
+	if calls.Load() != 2 {
+		t.Errorf("receiver called %d times, want 2", calls.Load())
+	}
+
+	// newest first
+	if deliveries[1].Status != models.DeliveryFailed || deliveries[1].StatusCode != http.StatusInternalServerError {
+		t.Errorf("first attempt %+v, want a failed 500", deliveries[1])
+	}
+
+	if deliveries[0].Status != models.DeliverySucceeded || deliveries[0].Attempt != 2 {
+		t.Errorf("second attempt %+v, want attempt 2 to succeed", deliveries[0])
+	}
+}
+
+func TestDeliveryDeadLetter(t *testing.T) {
+	webhook, _, _ := subscribe(t, http.StatusServiceUnavailable)
+
+	event := models.NewMovieEvent(models.EventMovieUpdated, 1, nil)
+	webhooks.Publish(context.Background(), event)
+
+	waitForDeliveries(t, webhook.Id, webhooks.MaxAttempts)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, letter := range database.FindDeadLetters(context.Background()) {
+			if letter.Event.Id != event.Id {
+				continue
+			}
+
+			if letter.WebhookId != webhook.Id || letter.Attempts != webhooks.MaxAttempts {
+				t.Errorf("dead letter %+v, want webhook %d after %d attempts", letter, webhook.Id, webhooks.MaxAttempts)
+			}
+
+			return
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatal("timed out waiting for the dead letter")
+}
+
+func TestBackoff(t *testing.T) {
+	webhooks.InitialBackoff = time.Second
+	webhooks.MaxBackoff = 10 * time.Second
+
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 5: 10 * time.Second, 100: 10 * time.Second} {
+		got := webhooks.Backoff(attempt)
+		if got < want/2 || got > want {
+			t.Errorf("backoff of attempt %d is %s, want between %s and %s", attempt, got, want/2, want)
+		}
+	}
+}
+
+func TestBackoffDoesNotOverflow(t *testing.T) {
+	webhooks.InitialBackoff = 10 * time.Minute
+	webhooks.MaxBackoff = 24 * time.Hour
+
+	for attempt := 1; attempt <= 100; attempt++ {
+		want := min(10*time.Minute<<min(attempt-1, 10), 24*time.Hour)
+
+		got := webhooks.Backoff(attempt)
+		if got < want/2 || got > want {
+			t.Errorf("backoff of attempt %d is %s, want between %s and %s", attempt, got, want/2, want)
+		}
+	}
+
+	// a maximum that isn't a doubling of the initial backoff is still reached
+	webhooks.InitialBackoff = 3 * time.Second
+	webhooks.MaxBackoff = 5 * time.Second
+
+	if got := webhooks.Backoff(2); got < 5*time.Second/2 {
+		t.Errorf("backoff of attempt 2 is %s, want between %s and %s", got, 5*time.Second/2, 5*time.Second)
+	}
+}
+
+func TestStopDeadLettersQueuedDeliveries(t *testing.T) {
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	webhooks.MaxAttempts = 3
+	webhooks.Timeout = time.Second
+	// receivers are served on loopback
+	webhooks.AllowedHosts = []string{"127.0.0.1"}
+	stop := webhooks.Start(1)
+
+	// every delivery takes a while, so the rest wait in the queue
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer receiver.Close()
+
+	webhook, err := database.CreateWebhook(context.Background(), models.Webhook{
+		Url:    receiver.URL,
+		Events: []models.EventType{models.EventMovieUpdated},
+		Active: true,
+		Secret: secret,
+	})
+	if err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+	defer database.DeleteWebhook(context.Background(), webhook.Id)
+
+	queued := map[string]bool{}
+	for range 5 {
+		event := models.NewMovieEvent(models.EventMovieUpdated, 1, nil)
+		queued[event.Id] = true
+		webhooks.Publish(context.Background(), event)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	if err := stop(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected stopping to run out of time, got %v", err)
+	}
+
+	// only the delivery being sent is waited for
+	if elapsed := time.Since(started); elapsed > 600*time.Millisecond {
+		t.Errorf("expected stopping not to deliver the queued events, took %s", elapsed)
+	}
+
+	letters := 0
+	for _, letter := range database.FindDeadLetters(context.Background()) {
+		if queued[letter.Event.Id] {
+			letters++
+		}
+	}
+
+	if delivered := int(calls.Load()); delivered+letters != len(queued) || letters == 0 {
+		t.Errorf("expected the %d events not delivered to be dead-lettered, got %d", len(queued)-delivered, letters)
+	}
+}
+
+func TestCheckUrl(t *testing.T) {
+	webhooks.AllowedHosts = []string{"receiver.internal"}
+
+	for rawUrl, allowed := range map[string]bool{
+		"https://93.184.215.14/hooks":             true,
+		"http://169.254.169.254/latest/meta-data": false,
+		"http://127.0.0.1:8080/hooks":             false,
+		"http://[::1]/hooks":                      false,
+		"http://10.0.0.5/hooks":                   false,
+		"http://192.168.1.1/hooks":                false,
+		"http://[::ffff:127.0.0.1]/hooks":         false,
+		"http://localhost/hooks":                  false,
+		"http://receiver.internal:9000/hooks":     true,
+	} {
+		err := webhooks.CheckUrl(context.Background(), rawUrl)
+		if allowed && err != nil {
+			t.Errorf("expected %s to be allowed, got %v", rawUrl, err)
+		}
+
+		if !allowed && !errors.Is(err, webhooks.ErrForbiddenTarget) {
+			t.Errorf("expected %s to be rejected, got %v", rawUrl, err)
+		}
+	}
+}
+
+func TestDeliveryRefusesPrivateAddresses(t *testing.T) {
+	allowed, calls, _ := subscribe(t, http.StatusOK)
+
+	// the same receiver by a host that isn't allowed
+	webhook, err := database.CreateWebhook(context.Background(), models.Webhook{
+		Url:    strings.Replace(allowed.Url, "127.0.0.1", "localhost", 1),
+		Events: []models.EventType{models.EventMovieUpdated},
+		Active: true,
+		Secret: secret,
+	})
+	if err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+	defer database.DeleteWebhook(context.Background(), webhook.Id)
+
+	webhooks.Publish(context.Background(), models.NewMovieEvent(models.EventMovieUpdated, 1, nil))
+
+	deliveries := waitForDeliveries(t, webhook.Id, 1)
+	waitForDeliveries(t, allowed.Id, 1)
+
+	if calls.Load() != 1 || deliveries[0].Status != models.DeliveryFailed {
+		t.Errorf("expected the delivery to localhost to fail without reaching it, got %+v", deliveries[0])
+	}
+}